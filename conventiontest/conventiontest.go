@@ -0,0 +1,54 @@
+// Package conventiontest runs the tests of a convention file from Go
+// tests, so a convention change that renames the examples fails go test:
+//
+//	func TestNamingConvention(t *testing.T) {
+//		conventiontest.Verify(t, "naming.json", map[string]string{"LABEL_STAGE": "dev"})
+//	}
+package conventiontest
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/cloudfluent/terraform-provider-label/internal/provider"
+)
+
+// Verify resolves the convention file at path with vars as the LABEL_*
+// variables, like the verify command does with the environment, and runs
+// each of its tests as a subtest. A nil vars ignores the environment.
+func Verify(t *testing.T, path string, vars map[string]string) {
+	t.Helper()
+
+	file, err := provider.LoadConventionFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg, errs := provider.ResolveConfig(provider.Settings{}, file.Settings, file.Source(), vars, os.Getwd)
+	if len(errs) > 0 {
+		all := make([]error, len(errs))
+		for i, e := range errs {
+			all[i] = e
+		}
+		t.Fatal(errors.Join(all...))
+	}
+	if len(file.Tests) == 0 {
+		t.Fatalf("%s declares no tests", path)
+	}
+
+	for _, test := range file.Tests {
+		t.Run(test.Title(), func(t *testing.T) {
+			diffs, err := test.Run(cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, d := range diffs {
+				if d.Missing {
+					t.Errorf("%s is not generated, want %q", d.Field, d.Want)
+					continue
+				}
+				t.Errorf("%s = %q, want %q", d.Field, d.Got, d.Want)
+			}
+		})
+	}
+}
@@ -0,0 +1,7 @@
+package conventiontest
+
+import "testing"
+
+func TestVerify(t *testing.T) {
+	Verify(t, "testdata/naming.json", nil)
+}
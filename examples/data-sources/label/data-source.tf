@@ -27,6 +27,23 @@ data "label" "db" {
 }
 # => id: dpl_ane2_db_dev_refined_sales_api
 
+# Cross-stage shared resource
+data "label" "kms_shared" {
+  resource_type = "kms"
+  stage         = "shared"
+  workspace     = "core"
+}
+# => id: dpl-ane2-kms-shared-core
+
+# Globally unique name with a deterministic suffix
+data "label" "logs" {
+  resource_type = "s3"
+  qualifier     = "logs"
+  unique_suffix = true
+  suffix_seed   = [data.aws_caller_identity.current.account_id, "dpl", "sales-api"]
+}
+# => id: dpl-ane2-s3-dev-logs-sales-api-<6 characters>
+
 # Multiple resources of the same type via for_each
 data "label" "sgs" {
   for_each      = toset(["emr", "msk", "vpce"])
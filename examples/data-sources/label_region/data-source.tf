@@ -0,0 +1,17 @@
+# Region name to abbreviation
+data "label_region" "current" {
+  region = "ap-northeast-2"
+}
+# => code: ane2, short_code: ane2, fixed_code: an2
+
+# Abbreviation back to region name
+data "label_region" "gcp" {
+  code  = "ane3"
+  cloud = "gcp"
+}
+# => region: asia-northeast3
+
+# Usage example
+provider "aws" {
+  region = data.label_region.current.region
+}
@@ -0,0 +1,9 @@
+output "environment" {
+  value = provider::label::region_code("ap-northeast-2")
+}
+# => ane2
+
+output "environment_fixed" {
+  value = provider::label::region_code("ap-northeast-2", "fixed")
+}
+# => an2
@@ -0,0 +1,9 @@
+output "region" {
+  value = provider::label::region_name("ane2")
+}
+# => ap-northeast-2
+
+output "azure_region" {
+  value = provider::label::region_name("krc", "azure")
+}
+# => koreacentral
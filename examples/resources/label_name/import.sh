@@ -0,0 +1,2 @@
+# Adopt an existing name, e.g. one created before the convention was introduced
+terraform import label_name.orders_db legacy-orders-db
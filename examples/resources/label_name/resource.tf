@@ -0,0 +1,21 @@
+# Pin a database name so that later convention changes do not replace it
+resource "label_name" "orders_db" {
+  resource_type = "db"
+  qualifier     = "orders"
+
+  keepers = {
+    # Bump to adopt the current convention (replaces the database)
+    generation = "1"
+  }
+}
+# => name: dpl-ane2-db-dev-orders-sales-api
+
+resource "aws_db_instance" "orders" {
+  identifier = label_name.orders_db.name
+  # ...
+}
+
+# Warn when the stored name no longer matches the convention
+output "orders_db_drift" {
+  value = label_name.orders_db.drift
+}
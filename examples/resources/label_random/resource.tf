@@ -0,0 +1,17 @@
+# Non-guessable bucket name within the S3 limit of 63 characters
+resource "label_random" "logs" {
+  resource_type = "s3"
+  qualifier     = "logs"
+  length        = 8
+  max_length    = 63
+
+  keepers = {
+    # Bump to generate a new suffix (replaces the bucket)
+    generation = "1"
+  }
+}
+# => name: dpl-ane2-s3-dev-logs-sales-api-<8 random characters>
+
+resource "aws_s3_bucket" "logs" {
+  bucket = label_random.logs.name
+}
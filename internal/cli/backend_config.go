@@ -0,0 +1,162 @@
+package cli
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/cloudfluent/terraform-provider-label/internal/provider"
+)
+
+func init() {
+	register("backend-config", "Print the S3 backend settings the convention derives", runBackendConfig)
+}
+
+// Output formats of backend-config.
+const (
+	formatArgs = "args"
+	formatHCL  = "hcl"
+)
+
+// safeArgPattern matches values that stay one word, as they are, in
+// terraform init $(terraform-provider-label backend-config).
+var safeArgPattern = regexp.MustCompile(`^[A-Za-z0-9_.,:@%+=/-]+$`)
+
+// backendSetting is a -backend-config key and value.
+type backendSetting struct {
+	Key   string
+	Value string
+}
+
+func runBackendConfig(env *Env) int {
+	fs := env.flagSet("backend-config")
+	fs.Usage = func() {
+		fmt.Fprintln(env.Stderr, "Usage: terraform-provider-label backend-config [flags]")
+		fmt.Fprintln(env.Stderr)
+		fmt.Fprintln(env.Stderr, "Prints the bucket, key and dynamodb_table of the S3 backend for the")
+		fmt.Fprintln(env.Stderr, "convention, e.g. terraform init $(terraform-provider-label backend-config)")
+		fmt.Fprintln(env.Stderr)
+		fs.PrintDefaults()
+	}
+	conventionFile := fs.String("convention-file", "", "convention file (default $"+provider.ConventionFileEnvVar+")")
+	format := fs.String("format", formatArgs, "output format: args, hcl or json")
+	bucketQualifier := fs.String("bucket-qualifier", "tfstate", "qualifier of the state bucket name")
+	tableQualifier := fs.String("table-qualifier", "tflock", "qualifier of the lock table name, or empty for no lock table")
+	stateFile := fs.String("state-file", "terraform.tfstate", "last segment of the state key")
+	overrides := map[string]*string{}
+	for _, name := range provider.BuiltinComponents {
+		overrides[name] = fs.String(name, "", "override the "+name+" component")
+	}
+	if code, ok := env.parse(fs); !ok {
+		return code
+	}
+	if !slices.Contains([]string{formatArgs, formatHCL, formatJSON}, *format) {
+		return env.errorf(ExitUsage, "unknown format %q", *format)
+	}
+	if *stateFile == "" {
+		return env.errorf(ExitUsage, "--state-file must not be empty")
+	}
+
+	conv, err := env.loadConvention(*conventionFile)
+	if err != nil {
+		return env.errorf(ExitUsage, "%s", err)
+	}
+	set := map[string]string{}
+	fs.Visit(func(fl *flag.Flag) {
+		if v, ok := overrides[fl.Name]; ok {
+			set[fl.Name] = *v
+		}
+	})
+	cfg := conv.cfg.WithOverrides(set)
+	if err := complete(cfg); err != nil {
+		return env.errorf(ExitUsage, "%s", err)
+	}
+
+	settings := conv.backendConfig(cfg, *bucketQualifier, *tableQualifier, *stateFile)
+	switch *format {
+	case formatJSON:
+		out := map[string]string{}
+		for _, s := range settings {
+			out[s.Key] = s.Value
+		}
+		enc := json.NewEncoder(env.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(out); err != nil {
+			return env.errorf(ExitFail, "%s", err)
+		}
+	case formatHCL:
+		writeBackendHCL(env.Stdout, settings)
+	default:
+		// $(...) splits on whitespace and expands globs without removing
+		// quotes, so values are not quoted but must not need quoting.
+		args := make([]string, len(settings))
+		for i, s := range settings {
+			if !safeArgPattern.MatchString(s.Value) {
+				return env.errorf(ExitUsage, "%s %q cannot be passed unquoted to terraform init; use --format hcl or json", s.Key, s.Value)
+			}
+			args[i] = "-backend-config=" + s.Key + "=" + s.Value
+		}
+		fmt.Fprintln(env.Stdout, strings.Join(args, " "))
+	}
+	return ExitOK
+}
+
+// backendConfig derives the S3 backend settings from cfg. The bucket and
+// lock table are shared by the workspaces of a stage, so their names leave
+// the workspace out; the key is the provider-level components in label
+// order, e.g. dpl/ane2/dev/sales-api/terraform.tfstate. Legacy names
+// apply to the bucket and table like to any other resource.
+func (c *convention) backendConfig(cfg *provider.LabelConfig, bucketQualifier string, tableQualifier string, stateFile string) []backendSetting {
+	shared := cfg.WithOverrides(map[string]string{"workspace": ""})
+	name := func(resourceType string, qualifier string) string {
+		target := c.targets[resourceType]
+		id := provider.GenerateID(shared, target.Abbreviation, qualifier, "", target.Delimiter)
+		if legacy, ok := shared.LegacyName("", id); ok {
+			return legacy
+		}
+		return id
+	}
+
+	order := cfg.LabelOrder
+	if order == nil {
+		order = provider.DefaultLabelOrder
+	}
+	var key []string
+	for _, component := range order {
+		if slices.Contains(provider.ResourceComponents, component) {
+			continue
+		}
+		if v, _ := cfg.Component(component); v != "" {
+			key = append(key, v)
+		}
+	}
+	key = append(key, stateFile)
+
+	settings := []backendSetting{
+		{"bucket", name("aws_s3_bucket", bucketQualifier)},
+		{"key", strings.Join(key, "/")},
+	}
+	if cfg.Region != "" {
+		settings = append(settings, backendSetting{"region", cfg.Region})
+	}
+	if tableQualifier != "" {
+		settings = append(settings, backendSetting{"dynamodb_table", name("aws_dynamodb_table", tableQualifier)})
+	}
+	return settings
+}
+
+// writeBackendHCL prints settings as a backend configuration file for
+// terraform init -backend-config=<file>.
+func writeBackendHCL(w io.Writer, settings []backendSetting) {
+	width := 0
+	for _, s := range settings {
+		width = max(width, len(s.Key))
+	}
+	for _, s := range settings {
+		fmt.Fprintf(w, "%-*s = %q\n", width, s.Key, s.Value)
+	}
+}
@@ -0,0 +1,103 @@
+package cli
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestBackendConfig(t *testing.T) {
+	tests := []struct {
+		name       string
+		convention string
+		args       []string
+		want       string
+	}{
+		{
+			name: "args",
+			want: "-backend-config=bucket=dpl-ane2-s3-dev-tfstate -backend-config=key=dpl/ane2/dev/sales-api/terraform.tfstate -backend-config=dynamodb_table=dpl-ane2-ddb-dev-tflock\n",
+		},
+		{
+			name:       "region, stage override and no lock table",
+			convention: `{"region": "ap-northeast-2", "legacy_names": {"dpl-ane2-s3-dev-tfstate": "acme-terraform-state"}}`,
+			args:       []string{"--stage", "prd", "--table-qualifier", ""},
+			want:       "-backend-config=bucket=dpl-ane2-s3-prd-tfstate -backend-config=key=dpl/ane2/prd/sales-api/terraform.tfstate -backend-config=region=ap-northeast-2\n",
+		},
+		{
+			name:       "legacy bucket",
+			convention: `{"legacy_names": {"dpl-ane2-s3-dev-tfstate": "acme-terraform-state"}}`,
+			args:       []string{"--format", "hcl", "--state-file", "network.tfstate"},
+			want: `bucket         = "acme-terraform-state"
+key            = "dpl/ane2/dev/sales-api/network.tfstate"
+dynamodb_table = "dpl-ane2-ddb-dev-tflock"
+`,
+		},
+		{
+			name:       "custom component in label order",
+			convention: `{"components": {"account": "core"}, "label_order": ["tenant", "account", "environment", "resource_type", "stage", "qualifier", "workspace"]}`,
+			args:       []string{"--workspace", "billing"},
+			want:       "-backend-config=bucket=dpl-core-ane2-s3-dev-tfstate -backend-config=key=dpl/core/ane2/dev/billing/terraform.tfstate -backend-config=dynamodb_table=dpl-core-ane2-ddb-dev-tflock\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vars := map[string]string{}
+			for k, v := range testVars {
+				vars[k] = v
+			}
+			if tt.convention != "" {
+				vars["LABEL_CONVENTION_FILE"] = writeFile(t, "convention.json", tt.convention)
+			}
+			code, stdout, stderr := run(t, vars, append([]string{"backend-config"}, tt.args...)...)
+			if code != ExitOK {
+				t.Fatalf("exit code = %d, want 0 (stderr %q)", code, stderr)
+			}
+			if stdout != tt.want {
+				t.Errorf("stdout:\n%s\nwant:\n%s", stdout, tt.want)
+			}
+		})
+	}
+}
+
+func TestBackendConfig_JSON(t *testing.T) {
+	code, stdout, stderr := run(t, testVars, "backend-config", "--format", "json")
+	if code != ExitOK {
+		t.Fatalf("exit code = %d, want 0 (stderr %q)", code, stderr)
+	}
+	var got map[string]string
+	if err := json.Unmarshal([]byte(stdout), &got); err != nil {
+		t.Fatalf("invalid JSON %q: %v", stdout, err)
+	}
+	if got["bucket"] != "dpl-ane2-s3-dev-tfstate" || got["key"] != "dpl/ane2/dev/sales-api/terraform.tfstate" || len(got) != 3 {
+		t.Errorf("unexpected settings: %v", got)
+	}
+}
+
+func TestBackendConfig_Usage(t *testing.T) {
+	if code, _, _ := run(t, map[string]string{"LABEL_TENANT": "dpl"}, "backend-config"); code != ExitUsage {
+		t.Errorf("incomplete convention: exit code = %d, want %d", code, ExitUsage)
+	}
+	if code, _, _ := run(t, testVars, "backend-config", "--format", "yaml"); code != ExitUsage {
+		t.Errorf("unknown format: exit code = %d, want %d", code, ExitUsage)
+	}
+}
+
+func TestBackendConfig_UnsafeValue(t *testing.T) {
+	// A value that $(...) would split or expand is refused in args format.
+	code, stdout, stderr := run(t, testVars, "backend-config", "--state-file", "my state.tfstate")
+	if code != ExitUsage || stdout != "" || !strings.Contains(stderr, `key "dpl/ane2/dev/sales-api/my state.tfstate"`) {
+		t.Errorf("exit code = %d, stdout %q, stderr %q", code, stdout, stderr)
+	}
+	for _, value := range []string{"*.tfstate", "$HOME.tfstate", "it's.tfstate"} {
+		if code, _, _ := run(t, testVars, "backend-config", "--state-file", value); code != ExitUsage {
+			t.Errorf("state file %q: exit code = %d, want %d", value, code, ExitUsage)
+		}
+	}
+
+	// Other formats quote the value.
+	code, stdout, stderr = run(t, testVars, "backend-config", "--format", "hcl", "--state-file", "my state.tfstate")
+	if code != ExitOK || !strings.Contains(stdout, `key            = "dpl/ane2/dev/sales-api/my state.tfstate"`) {
+		t.Errorf("hcl: exit code = %d, stdout %q, stderr %q", code, stdout, stderr)
+	}
+}
@@ -0,0 +1,154 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+
+	tfjson "github.com/hashicorp/terraform-json"
+)
+
+func init() {
+	register("check-plan", "Check names and tags in a plan against the convention", runCheckPlan)
+}
+
+// Output formats of the report commands.
+const (
+	formatHuman = "human"
+	formatSARIF = "sarif"
+)
+
+// planReport is the JSON output of check-plan.
+type planReport struct {
+	// Checked is the number of planned resources in the target catalog.
+	Checked int `json:"checked"`
+	// Legacy are the addresses of resources that keep a legacy name.
+	Legacy []string `json:"legacy"`
+	// Unknown are the addresses of resources whose name is only known
+	// after apply.
+	Unknown    []string    `json:"unknown"`
+	Violations []violation `json:"violations"`
+}
+
+func runCheckPlan(env *Env) int {
+	fs := env.flagSet("check-plan")
+	fs.Usage = func() {
+		fmt.Fprintln(env.Stderr, "Usage: terraform-provider-label check-plan [flags] <plan.json | ->")
+		fmt.Fprintln(env.Stderr)
+		fmt.Fprintln(env.Stderr, "Reads the output of 'terraform show -json <planfile>' and exits with 1 when")
+		fmt.Fprintln(env.Stderr, "a created or updated resource breaks the convention.")
+		fmt.Fprintln(env.Stderr)
+		fs.PrintDefaults()
+	}
+	conventionFile := fs.String("convention-file", "", "convention file (default $LABEL_CONVENTION_FILE)")
+	format := fs.String("format", formatHuman, "output format: human, json or sarif")
+	if code, ok := env.parse(fs); !ok {
+		return code
+	}
+
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return ExitUsage
+	}
+	if !slices.Contains([]string{formatHuman, formatJSON, formatSARIF}, *format) {
+		return env.errorf(ExitUsage, "unknown format %q", *format)
+	}
+
+	conv, err := env.loadConvention(*conventionFile)
+	if err != nil {
+		return env.errorf(ExitUsage, "%s", err)
+	}
+	if err := complete(conv.cfg); err != nil {
+		return env.errorf(ExitUsage, "%s", err)
+	}
+
+	plan, err := env.readPlan(fs.Arg(0))
+	if err != nil {
+		return env.errorf(ExitUsage, "%s", err)
+	}
+	report := conv.checkPlan(plan)
+
+	switch *format {
+	case formatJSON:
+		enc := json.NewEncoder(env.Stdout)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(report)
+	case formatSARIF:
+		err = writeSARIF(env.Stdout, report.Violations)
+	default:
+		writePlanReport(env.Stdout, report)
+	}
+	if err != nil {
+		return env.errorf(ExitFail, "%s", err)
+	}
+
+	if len(report.Violations) > 0 {
+		return ExitFail
+	}
+	return ExitOK
+}
+
+// readPlan decodes a JSON plan from the file name, or stdin for "-".
+func (env *Env) readPlan(name string) (*tfjson.Plan, error) {
+	f, err := env.open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var plan tfjson.Plan
+	if err := json.NewDecoder(f).Decode(&plan); err != nil {
+		return nil, fmt.Errorf("reading plan %s: %w", name, err)
+	}
+	return &plan, nil
+}
+
+// checkPlan assesses every managed resource in the target catalog that the
+// plan creates or updates.
+func (c *convention) checkPlan(plan *tfjson.Plan) planReport {
+	report := planReport{Legacy: []string{}, Unknown: []string{}, Violations: []violation{}}
+	for _, rc := range plan.ResourceChanges {
+		if rc.Mode != tfjson.ManagedResourceMode || rc.Change == nil {
+			continue
+		}
+		if actions := rc.Change.Actions; !actions.Create() && !actions.Update() && !actions.Replace() {
+			continue
+		}
+		target, ok := c.targets[rc.Type]
+		if !ok {
+			continue
+		}
+
+		after, _ := rc.Change.After.(map[string]any)
+		a := c.assess(rc.Address, rc.Type, target, after, rc.Change.AfterUnknown)
+		report.Checked++
+		switch {
+		case !a.Known:
+			report.Unknown = append(report.Unknown, rc.Address)
+		case a.Legacy:
+			report.Legacy = append(report.Legacy, rc.Address)
+		}
+		report.Violations = append(report.Violations, a.Violations...)
+	}
+	return report
+}
+
+func writePlanReport(w io.Writer, report planReport) {
+	failed := map[string]bool{}
+	for _, v := range report.Violations {
+		fmt.Fprintf(w, "%s: %s\n", v.Address, v.Message)
+		failed[v.Address] = true
+	}
+	for _, address := range report.Unknown {
+		fmt.Fprintf(w, "%s: name is known after apply, not checked\n", address)
+	}
+	for _, address := range report.Legacy {
+		fmt.Fprintf(w, "%s: keeps a legacy name\n", address)
+	}
+
+	if len(report.Violations) > 0 || len(report.Unknown) > 0 || len(report.Legacy) > 0 {
+		fmt.Fprintln(w)
+	}
+	fmt.Fprintf(w, "%d violation(s) in %d of %d checked resource(s)\n", len(report.Violations), len(failed), report.Checked)
+}
@@ -0,0 +1,258 @@
+package cli
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+const testPlan = `{
+  "format_version": "1.2",
+  "terraform_version": "1.9.0",
+  "resource_changes": [
+    {
+      "address": "aws_security_group.emr",
+      "mode": "managed",
+      "type": "aws_security_group",
+      "name": "emr",
+      "change": {
+        "actions": ["create"],
+        "after": {
+          "name": "dpl-ane2-sg-dev-emr-sales-api",
+          "tags": {"Name": "dpl-ane2-sg-dev-emr-sales-api"},
+          "tags_all": {"Name": "dpl-ane2-sg-dev-emr-sales-api", "Tenant": "dpl", "Environment": "ane2", "Stage": "dev"}
+        },
+        "after_unknown": {"id": true}
+      }
+    },
+    {
+      "address": "aws_security_group.web",
+      "mode": "managed",
+      "type": "aws_security_group",
+      "name": "web",
+      "change": {
+        "actions": ["update"],
+        "after": {
+          "name": "web-sg",
+          "tags": {"Tenant": "dpl", "Stage": "prd"},
+          "tags_all": null
+        },
+        "after_unknown": {}
+      }
+    },
+    {
+      "address": "aws_db_instance.orders",
+      "mode": "managed",
+      "type": "aws_db_instance",
+      "name": "orders",
+      "change": {
+        "actions": ["create"],
+        "after": {
+          "identifier": "ordersprod",
+          "tags": {"Tenant": "dpl", "Environment": "ane2", "Stage": "dev"}
+        },
+        "after_unknown": {}
+      }
+    },
+    {
+      "address": "aws_s3_bucket.logs",
+      "mode": "managed",
+      "type": "aws_s3_bucket",
+      "name": "logs",
+      "change": {
+        "actions": ["create"],
+        "after": {"bucket": null, "tags": null},
+        "after_unknown": {"bucket": true, "tags_all": true}
+      }
+    },
+    {
+      "address": "aws_iam_role.old",
+      "mode": "managed",
+      "type": "aws_iam_role",
+      "name": "old",
+      "change": {
+        "actions": ["delete"],
+        "before": {"name": "legacy-role"},
+        "after": null
+      }
+    },
+    {
+      "address": "aws_instance.untracked",
+      "mode": "managed",
+      "type": "random_pet",
+      "name": "untracked",
+      "change": {"actions": ["create"], "after": {"id": "x"}}
+    }
+  ]
+}`
+
+func TestCheckPlan(t *testing.T) {
+	convention := writeFile(t, "convention.json", `{"legacy_names": {"dpl-ane2-db-dev-orders-sales-api": "ordersprod"}}`)
+	vars := map[string]string{"LABEL_CONVENTION_FILE": convention}
+	for k, v := range testVars {
+		vars[k] = v
+	}
+
+	code, stdout, stderr := runStdin(t, vars, testPlan, "check-plan", "--format", "json", "-")
+	if code != ExitFail {
+		t.Fatalf("exit code = %d, want %d (stderr %q)", code, ExitFail, stderr)
+	}
+
+	var report planReport
+	if err := json.Unmarshal([]byte(stdout), &report); err != nil {
+		t.Fatalf("invalid JSON %q: %v", stdout, err)
+	}
+	if report.Checked != 4 {
+		t.Errorf("checked = %d, want 4", report.Checked)
+	}
+	if len(report.Legacy) != 1 || report.Legacy[0] != "aws_db_instance.orders" {
+		t.Errorf("legacy = %v, want [aws_db_instance.orders]", report.Legacy)
+	}
+	if len(report.Unknown) != 1 || report.Unknown[0] != "aws_s3_bucket.logs" {
+		t.Errorf("unknown = %v, want [aws_s3_bucket.logs]", report.Unknown)
+	}
+
+	var got []string
+	for _, v := range report.Violations {
+		got = append(got, v.Address+" "+v.Rule+" "+v.Actual)
+	}
+	want := []string{
+		"aws_security_group.web name-convention web-sg",
+		"aws_security_group.web tag-missing ",
+		"aws_security_group.web tag-value prd",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("violations:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestCheckPlan_Formats(t *testing.T) {
+	code, stdout, _ := runStdin(t, testVars, testPlan, "check-plan", "-")
+	if code != ExitFail {
+		t.Fatalf("exit code = %d, want %d", code, ExitFail)
+	}
+	for _, want := range []string{
+		`aws_security_group.web: name "web-sg" does not follow the convention for sg (e.g. "dpl-ane2-sg-dev-sales-api")`,
+		"aws_s3_bucket.logs: name is known after apply, not checked",
+		"4 violation(s) in 2 of 4 checked resource(s)",
+	} {
+		if !strings.Contains(stdout, want) {
+			t.Errorf("human output missing %q:\n%s", want, stdout)
+		}
+	}
+
+	code, stdout, _ = runStdin(t, testVars, testPlan, "check-plan", "--format", "sarif", "-")
+	if code != ExitFail {
+		t.Fatalf("exit code = %d, want %d", code, ExitFail)
+	}
+	var log sarifLog
+	if err := json.Unmarshal([]byte(stdout), &log); err != nil {
+		t.Fatalf("invalid SARIF %q: %v", stdout, err)
+	}
+	if log.Version != "2.1.0" || len(log.Runs) != 1 || len(log.Runs[0].Results) != 4 || len(log.Runs[0].Tool.Driver.Rules) != 3 {
+		t.Errorf("unexpected SARIF log: %+v", log)
+	}
+	if loc := log.Runs[0].Results[0].Locations[0].LogicalLocations[0]; loc.FullyQualifiedName != "aws_security_group.web" {
+		t.Errorf("first result location = %+v", loc)
+	}
+}
+
+func TestCheckPlan_Compliant(t *testing.T) {
+	plan := `{"format_version": "1.2", "resource_changes": [{
+		"address": "aws_security_group.emr", "mode": "managed", "type": "aws_security_group",
+		"change": {"actions": ["create"], "after": {"name": "dpl-ane2-sg-dev-emr-sales-api", "tags": {"Tenant": "dpl", "Environment": "ane2", "Stage": "dev"}}}
+	}]}`
+	code, stdout, stderr := runStdin(t, testVars, plan, "check-plan", "-")
+	if code != ExitOK {
+		t.Fatalf("exit code = %d, want 0 (stdout %q, stderr %q)", code, stdout, stderr)
+	}
+}
+
+func TestCheckPlan_Usage(t *testing.T) {
+	if code, _, _ := run(t, testVars, "check-plan"); code != ExitUsage {
+		t.Errorf("no plan: exit code = %d, want %d", code, ExitUsage)
+	}
+	if code, _, _ := runStdin(t, testVars, "not json", "check-plan", "-"); code != ExitUsage {
+		t.Errorf("invalid plan: exit code = %d, want %d", code, ExitUsage)
+	}
+}
+
+func TestCheckPlan_Overrides(t *testing.T) {
+	tests := []struct {
+		name string
+		vars map[string]string
+		plan string
+		want int
+	}{
+		{
+			name: "stage and workspace override",
+			plan: `{"format_version": "1.2", "resource_changes": [{
+				"address": "aws_sqs_queue.shared", "mode": "managed", "type": "aws_sqs_queue",
+				"change": {"actions": ["create"], "after": {"name": "dpl-ane2-sqs-shared-core", "tags": {"Tenant": "dpl", "Environment": "ane2", "Stage": "shared"}}}
+			}]}`,
+			want: ExitOK,
+		},
+		{
+			name: "context tenant without workspace",
+			plan: `{"format_version": "1.2", "resource_changes": [{
+				"address": "aws_security_group.ctx", "mode": "managed", "type": "aws_security_group",
+				"change": {"actions": ["create"], "after": {"name": "acme-ane2-sg-dev-emr", "tags": {"Tenant": "acme", "Environment": "ane2", "Stage": "dev"}}}
+			}]}`,
+			want: ExitOK,
+		},
+		{
+			name: "untagged resource",
+			plan: `{"format_version": "1.2", "resource_changes": [{
+				"address": "aws_glue_catalog_database.shared", "mode": "managed", "type": "aws_glue_catalog_database",
+				"change": {"actions": ["create"], "after": {"name": "dpl_ane2_db_shared_refined"}}
+			}]}`,
+			want: ExitOK,
+		},
+		{
+			name: "name and tags disagree",
+			plan: `{"format_version": "1.2", "resource_changes": [{
+				"address": "aws_security_group.x", "mode": "managed", "type": "aws_security_group",
+				"change": {"actions": ["create"], "after": {"name": "dpl-ane2-sg-prd-sales-api", "tags": {"Tenant": "dpl", "Environment": "ane2", "Stage": "dev"}}}
+			}]}`,
+			want: ExitFail,
+		},
+		{
+			name: "tags known after apply",
+			plan: `{"format_version": "1.2", "resource_changes": [{
+				"address": "aws_security_group.x", "mode": "managed", "type": "aws_security_group",
+				"change": {"actions": ["create"], "after": {"name": "dpl-ane2-sg-prd-sales-api", "tags": null}, "after_unknown": {"tags": true, "tags_all": true}}
+			}]}`,
+			want: ExitFail,
+		},
+		{
+			name: "tag values known after apply",
+			plan: `{"format_version": "1.2", "resource_changes": [{
+				"address": "aws_security_group.x", "mode": "managed", "type": "aws_security_group",
+				"change": {"actions": ["create"], "after": {"name": "dpl-ane2-sg-dev-sales-api", "tags": {"Tenant": "dpl", "Environment": "ane2"}}, "after_unknown": {"tags": {"Stage": true}}}
+			}]}`,
+			want: ExitOK,
+		},
+		{
+			name: "null-label",
+			vars: map[string]string{"LABEL_COMPAT": "null-label", "LABEL_NAMESPACE": "eg", "LABEL_STAGE": "prod"},
+			plan: `{"format_version": "1.2", "resource_changes": [{
+				"address": "aws_instance.app", "mode": "managed", "type": "aws_instance",
+				"change": {"actions": ["create"], "after": {"tags": {"Name": "eg-prod-app-blue", "Namespace": "eg", "Stage": "prod", "Attributes": "blue"}}}
+			}]}`,
+			want: ExitOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vars := tt.vars
+			if vars == nil {
+				vars = testVars
+			}
+			code, stdout, stderr := runStdin(t, vars, tt.plan, "check-plan", "-")
+			if code != tt.want {
+				t.Errorf("exit code = %d, want %d (stdout %q, stderr %q)", code, tt.want, stdout, stderr)
+			}
+		})
+	}
+}
@@ -0,0 +1,178 @@
+// Package cli implements the subcommands the provider binary runs when it
+// is invoked outside Terraform, e.g.
+//
+//	terraform-provider-label name --resource-type sg --qualifier emr
+//
+// Subcommands read the same LABEL_* environment variables and convention
+// file as the provider, so scripts and Terraform generate the same names.
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/cloudfluent/terraform-provider-label/internal/provider"
+)
+
+// Exit codes.
+const (
+	ExitOK    = 0
+	ExitFail  = 1 // the command ran and found problems
+	ExitUsage = 2 // invalid arguments or configuration
+)
+
+// Env is the environment a subcommand runs in.
+type Env struct {
+	Args   []string
+	Vars   map[string]string
+	Stdin  io.Reader
+	Stdout io.Writer
+	Stderr io.Writer
+	Getwd  func() (string, error)
+}
+
+type command struct {
+	name    string
+	summary string
+	run     func(env *Env) int
+}
+
+var commands []command
+
+func register(name string, summary string, run func(env *Env) int) {
+	commands = append(commands, command{name, summary, run})
+}
+
+// Run dispatches args (without the program name) to a subcommand and
+// returns the process exit code.
+func Run(args []string, vars map[string]string, stdin io.Reader, stdout io.Writer, stderr io.Writer) int {
+	if len(args) == 0 || args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
+		usage(stderr)
+		if len(args) == 0 {
+			return ExitUsage
+		}
+		return ExitOK
+	}
+
+	i := slices.IndexFunc(commands, func(c command) bool { return c.name == args[0] })
+	if i < 0 {
+		fmt.Fprintf(stderr, "unknown command %q\n\n", args[0])
+		usage(stderr)
+		return ExitUsage
+	}
+	return commands[i].run(&Env{
+		Args:   args[1:],
+		Vars:   vars,
+		Stdin:  stdin,
+		Stdout: stdout,
+		Stderr: stderr,
+		Getwd:  os.Getwd,
+	})
+}
+
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "Usage: terraform-provider-label <command> [flags]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Without a command the binary serves the Terraform plugin protocol.")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+	for _, c := range commands {
+		fmt.Fprintf(w, "  %-20s %s\n", c.name, c.summary)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Run '<command> -h' for the flags of a command.")
+}
+
+// flagSet returns a flag set for the named command that reports errors to
+// env.Stderr.
+func (env *Env) flagSet(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(env.Stderr)
+	return fs
+}
+
+// parse parses env.Args into fs. ok is false when the command should exit
+// with code.
+func (env *Env) parse(fs *flag.FlagSet) (code int, ok bool) {
+	if err := fs.Parse(env.Args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return ExitOK, false
+		}
+		return ExitUsage, false
+	}
+	return ExitOK, true
+}
+
+// open opens the input file name, or stdin for "-".
+func (env *Env) open(name string) (io.ReadCloser, error) {
+	if name == "-" {
+		return io.NopCloser(env.Stdin), nil
+	}
+	return os.Open(name)
+}
+
+// errorf reports an error on stderr and returns code.
+func (env *Env) errorf(code int, format string, args ...any) int {
+	fmt.Fprintf(env.Stderr, "error: "+format+"\n", args...)
+	return code
+}
+
+// convention is a resolved naming convention, its target catalog and the
+// tests of its file.
+type convention struct {
+	cfg     *provider.LabelConfig
+	targets map[string]provider.Target
+	tests   []provider.ConventionTest
+}
+
+// loadConvention resolves the naming convention from the convention file
+// at path (or LABEL_CONVENTION_FILE) and the LABEL_* variables, the same
+// way the provider does for an empty provider block.
+func (env *Env) loadConvention(path string) (*convention, error) {
+	if path == "" {
+		path = env.Vars[provider.ConventionFileEnvVar]
+	}
+	return loadConvention(path, env.Vars, env.Getwd)
+}
+
+func loadConvention(path string, vars map[string]string, getwd func() (string, error)) (*convention, error) {
+	var file *provider.ConventionFile
+	var settings provider.Settings
+	var source string
+	if path != "" {
+		var err error
+		file, err = provider.LoadConventionFile(path)
+		if err != nil {
+			return nil, err
+		}
+		settings = file.Settings
+		source = file.Source()
+	}
+
+	cfg, errs := provider.ResolveConfig(provider.Settings{}, settings, source, vars, getwd)
+	if len(errs) > 0 {
+		msgs := make([]string, len(errs))
+		for i, e := range errs {
+			msgs[i] = e.Error()
+		}
+		return nil, errors.New(strings.Join(msgs, "\n"))
+	}
+	conv := &convention{cfg: cfg, targets: file.Catalog()}
+	if file != nil {
+		conv.tests = file.Tests
+	}
+	return conv, nil
+}
+
+// complete returns an error naming the required components cfg lacks.
+func complete(cfg *provider.LabelConfig) error {
+	if missing := cfg.MissingComponents(); len(missing) > 0 {
+		return fmt.Errorf("missing required components: %s (set them with flags, LABEL_* variables or the convention file)", strings.Join(missing, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,183 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"maps"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// run runs the CLI with vars and returns the exit code, stdout and stderr.
+func run(t *testing.T, vars map[string]string, args ...string) (int, string, string) {
+	t.Helper()
+	return runStdin(t, vars, "", args...)
+}
+
+// runStdin is run with stdin.
+func runStdin(t *testing.T, vars map[string]string, stdin string, args ...string) (int, string, string) {
+	t.Helper()
+	var stdout, stderr bytes.Buffer
+	code := Run(args, vars, strings.NewReader(stdin), &stdout, &stderr)
+	return code, stdout.String(), stderr.String()
+}
+
+// writeFile writes content to name in a temporary directory and returns
+// its path.
+func writeFile(t *testing.T, name string, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+var testVars = map[string]string{
+	"LABEL_TENANT":      "dpl",
+	"LABEL_ENVIRONMENT": "ane2",
+	"LABEL_STAGE":       "dev",
+	"LABEL_WORKSPACE":   "sales-api",
+}
+
+func TestRun_Usage(t *testing.T) {
+	code, _, stderr := run(t, nil)
+	if code != ExitUsage || !strings.Contains(stderr, "Commands:") {
+		t.Errorf("no args: code %d, stderr %q", code, stderr)
+	}
+
+	code, _, stderr = run(t, nil, "bogus")
+	if code != ExitUsage || !strings.Contains(stderr, `unknown command "bogus"`) {
+		t.Errorf("unknown command: code %d, stderr %q", code, stderr)
+	}
+}
+
+func TestName(t *testing.T) {
+	convention := writeFile(t, "convention.json", `{
+		"tenant": "acme",
+		"environment": "use1",
+		"stage": "prd",
+		"legacy_names": {"acme-use1-db-prd-orders": "ordersprod"}
+	}`)
+
+	tests := []struct {
+		name     string
+		vars     map[string]string
+		args     []string
+		wantCode int
+		want     string
+	}{
+		{
+			name: "text",
+			vars: testVars,
+			args: []string{"name", "--resource-type", "sg", "--qualifier", "emr"},
+			want: "dpl-ane2-sg-dev-emr-sales-api\n",
+		},
+		{
+			name: "component override",
+			vars: testVars,
+			args: []string{"name", "--resource-type", "sg", "--stage", "prd"},
+			want: "dpl-ane2-sg-prd-sales-api\n",
+		},
+		{
+			name: "env over convention file",
+			vars: map[string]string{"LABEL_CONVENTION_FILE": convention, "LABEL_STAGE": "stg"},
+			args: []string{"name", "--resource-type", "db", "--qualifier", "orders"},
+			want: "acme-use1-db-stg-orders\n",
+		},
+		{
+			name: "legacy name from flag file",
+			args: []string{"name", "--convention-file", convention, "--resource-type", "db", "--qualifier", "orders"},
+			want: "ordersprod\n",
+		},
+		{
+			name: "shell",
+			vars: testVars,
+			args: []string{"name", "--resource-type", "sg", "--format", "shell", "--workspace", "it's"},
+			want: "export LABEL_ID='dpl-ane2-sg-dev-it'\\''s'\n" +
+				"export LABEL_TAG_ATTRIBUTES='it'\\''s'\n" +
+				"export LABEL_TAG_ENVIRONMENT='ane2'\n" +
+				"export LABEL_TAG_NAME='dpl-ane2-sg-dev-it'\\''s'\n" +
+				"export LABEL_TAG_STAGE='dev'\n" +
+				"export LABEL_TAG_TENANT='dpl'\n",
+		},
+		{
+			name:     "missing resource type",
+			vars:     testVars,
+			args:     []string{"name"},
+			wantCode: ExitUsage,
+		},
+		{
+			name:     "missing components",
+			args:     []string{"name", "--resource-type", "sg"},
+			wantCode: ExitUsage,
+		},
+		{
+			name:     "unknown format",
+			vars:     testVars,
+			args:     []string{"name", "--resource-type", "sg", "--format", "yaml"},
+			wantCode: ExitUsage,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, stdout, stderr := run(t, tt.vars, tt.args...)
+			if code != tt.wantCode {
+				t.Fatalf("exit code = %d, want %d (stderr %q)", code, tt.wantCode, stderr)
+			}
+			if tt.wantCode == ExitOK && stdout != tt.want {
+				t.Errorf("stdout = %q, want %q", stdout, tt.want)
+			}
+		})
+	}
+}
+
+func TestName_JSON(t *testing.T) {
+	code, stdout, stderr := run(t, testVars, "name", "--resource-type", "sg", "--qualifier", "emr", "--stage", "prd", "--format", "json")
+	if code != ExitOK {
+		t.Fatalf("exit code = %d (stderr %q)", code, stderr)
+	}
+
+	var got label
+	if err := json.Unmarshal([]byte(stdout), &got); err != nil {
+		t.Fatalf("invalid JSON %q: %v", stdout, err)
+	}
+	if got.ID != "dpl-ane2-sg-prd-emr-sales-api" || got.Tags["Name"] != got.ID || got.Tags["Attributes"] != "emr-sales-api" || got.IsLegacy {
+		t.Errorf("unexpected output: %+v", got)
+	}
+	wantSources := map[string]string{
+		"tenant":      "env LABEL_TENANT",
+		"environment": "env LABEL_ENVIRONMENT",
+		"stage":       "flag --stage",
+		"workspace":   "env LABEL_WORKSPACE",
+	}
+	if !maps.Equal(got.Sources, wantSources) {
+		t.Errorf("sources = %v, want %v", got.Sources, wantSources)
+	}
+}
+
+func TestEnvName(t *testing.T) {
+	tests := []struct {
+		key  string
+		want string
+	}{
+		{"Name", "NAME"},
+		{"NAME", "NAME"},
+		{"name", "NAME"},
+		{"CostCenter", "COST_CENTER"},
+		{"NAMESPACE", "NAMESPACE"},
+		{"Ec2Instance", "EC2_INSTANCE"},
+		{"team.name", "TEAM_NAME"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			if got := envName(tt.key); got != tt.want {
+				t.Errorf("envName(%q) = %q, want %q", tt.key, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,163 @@
+package cli
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/cloudfluent/terraform-provider-label/internal/provider"
+)
+
+// Compliance rules.
+const (
+	ruleName       = "name-convention"
+	ruleTagMissing = "tag-missing"
+	ruleTagValue   = "tag-value"
+)
+
+// ruleDescriptions describe the compliance rules, e.g. in SARIF output.
+var ruleDescriptions = map[string]string{
+	ruleName:       "Resource name does not follow the naming convention",
+	ruleTagMissing: "Resource is missing a convention tag",
+	ruleTagValue:   "Convention tag has a different value than the convention",
+}
+
+// violation is a resource that breaks a compliance rule.
+type violation struct {
+	Address  string `json:"address"`
+	Type     string `json:"type"`
+	Rule     string `json:"rule"`
+	Message  string `json:"message"`
+	Actual   string `json:"actual,omitempty"`
+	Expected string `json:"expected,omitempty"`
+}
+
+// assessment is the result of checking one resource against the convention.
+type assessment struct {
+	Name string
+	// Known is false when the name is not known, e.g. computed at apply.
+	Known      bool
+	Compliant  bool
+	Legacy     bool
+	Match      provider.NameMatch
+	Violations []violation
+}
+
+// assess checks the name and tags in values, the attribute values of a
+// resource, against the convention for target. afterUnknown is the
+// after_unknown of a planned change, or nil: tags that are only known after
+// apply are not checked.
+func (c *convention) assess(address string, resourceType string, target provider.Target, values map[string]any, afterUnknown any) assessment {
+	var a assessment
+	a.Name, a.Known = target.Name(values)
+
+	fail := func(rule string, actual string, expected string, format string, args ...any) {
+		a.Violations = append(a.Violations, violation{
+			Address:  address,
+			Type:     resourceType,
+			Rule:     rule,
+			Message:  fmt.Sprintf(format, args...),
+			Actual:   actual,
+			Expected: expected,
+		})
+	}
+
+	tags := stringMap(values["tags_all"])
+	if tags == nil {
+		tags = stringMap(values["tags"])
+	}
+
+	// Names generated with overrides, e.g. stage = "shared" on the data
+	// source, carry the override in their tags; they are checked against
+	// the convention with that override.
+	cfg := c.cfg
+	if a.Known {
+		switch match, ok := provider.ParseNameWith(c.cfg, target.Abbreviation, a.Name, target.Delimiter, c.parseOptions(target, tags)); {
+		case ok:
+			a.Match = match
+			if match.Overrides != nil {
+				cfg = c.cfg.WithOverrides(match.Overrides)
+			}
+		case c.cfg.IsLegacyName(a.Name):
+			a.Legacy = true
+		default:
+			example := provider.GenerateID(c.cfg, target.Abbreviation, "", "", target.Delimiter)
+			fail(ruleName, a.Name, example,
+				"name %q does not follow the convention for %s (e.g. %q)", a.Name, target.Abbreviation, example)
+		}
+	}
+
+	allUnknown, unknownKeys := unknownTags(afterUnknown)
+	if target.Tagged && !allUnknown {
+		expected := provider.GenerateTags(cfg, target.Abbreviation, "", "", target.Delimiter)
+		// Name and Attributes depend on the qualifier and instance key.
+		delete(expected, cfg.TagKey("name"))
+		delete(expected, cfg.TagKey("attributes"))
+		for _, key := range slices.Sorted(maps.Keys(expected)) {
+			actual, ok := tags[key]
+			switch {
+			case unknownKeys[key]:
+			case !ok:
+				fail(ruleTagMissing, "", expected[key], "tag %s is missing, want %q", key, expected[key])
+			case actual != expected[key]:
+				fail(ruleTagValue, actual, expected[key], "tag %s is %q, want %q", key, actual, expected[key])
+			}
+		}
+	}
+
+	a.Compliant = len(a.Violations) == 0
+	return a
+}
+
+// parseOptions returns the overrides a name of target may carry: the
+// values of the component tags of a tagged resource, or any value when
+// the resource has no tags to tell.
+func (c *convention) parseOptions(target provider.Target, tags map[string]string) provider.ParseOptions {
+	if !target.Tagged {
+		return provider.ParseOptions{AnyOverride: true}
+	}
+	opts := provider.ParseOptions{Overrides: map[string][]string{}}
+	for _, component := range append(slices.Clone(provider.BuiltinComponents), slices.Sorted(maps.Keys(c.cfg.Components))...) {
+		if v, ok := tags[c.cfg.TagKey(component)]; ok && component != "workspace" {
+			opts.Overrides[component] = []string{v}
+		}
+	}
+	return opts
+}
+
+// unknownTags reports whether the tags of a planned change are only known
+// after apply, either as a whole or for some keys.
+func unknownTags(afterUnknown any) (bool, map[string]bool) {
+	obj, _ := afterUnknown.(map[string]any)
+	keys := map[string]bool{}
+	for _, attr := range []string{"tags", "tags_all"} {
+		switch v := obj[attr].(type) {
+		case bool:
+			if v {
+				return true, nil
+			}
+		case map[string]any:
+			for key, unknown := range v {
+				if unknown == true {
+					keys[key] = true
+				}
+			}
+		}
+	}
+	return false, keys
+}
+
+// stringMap converts a decoded JSON object to a map of its string values.
+func stringMap(v any) map[string]string {
+	obj, ok := v.(map[string]any)
+	if !ok {
+		return nil
+	}
+	out := make(map[string]string, len(obj))
+	for k, v := range obj {
+		if s, ok := v.(string); ok {
+			out[k] = s
+		}
+	}
+	return out
+}
@@ -0,0 +1,374 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+
+	tfjson "github.com/hashicorp/terraform-json"
+
+	"github.com/cloudfluent/terraform-provider-label/internal/provider"
+)
+
+func init() {
+	register("diff", "Show the names and tags a convention change renames", runDiff)
+}
+
+// labelSpec is a label to compare: the inputs of a label data source and,
+// optionally, the Terraform type of the resource it names.
+type labelSpec struct {
+	Address      string `json:"address,omitempty"`
+	Type         string `json:"type,omitempty"`
+	ResourceType string `json:"resource_type,omitempty"`
+	Qualifier    string `json:"qualifier,omitempty"`
+	InstanceKey  string `json:"instance_key,omitempty"`
+	Delimiter    string `json:"delimiter,omitempty"`
+	LegacyKey    string `json:"legacy_key,omitempty"`
+	// Components override provider-level components in both conventions.
+	Components map[string]string `json:"components,omitempty"`
+}
+
+// describe identifies the spec in reports, by address when it has one.
+func (s labelSpec) describe() string {
+	if s.Address != "" {
+		return s.Address
+	}
+	var parts []string
+	for _, in := range []struct{ name, value string }{
+		{"type", s.Type},
+		{"resource_type", s.ResourceType},
+		{"qualifier", s.Qualifier},
+		{"instance_key", s.InstanceKey},
+	} {
+		if in.value != "" {
+			parts = append(parts, in.name+"="+in.value)
+		}
+	}
+	for _, name := range slices.Sorted(maps.Keys(s.Components)) {
+		parts = append(parts, name+"="+s.Components[name])
+	}
+	return strings.Join(parts, " ")
+}
+
+// diffReport is the JSON output of diff.
+type diffReport struct {
+	// Compared is the number of labels generated with both conventions.
+	Compared int           `json:"compared"`
+	Changes  []labelChange `json:"changes"`
+	Skipped  []skipped     `json:"skipped"`
+}
+
+// labelChange is a label whose ID or tags differ between the conventions.
+type labelChange struct {
+	Label        string      `json:"label"`
+	Type         string      `json:"type,omitempty"`
+	ResourceType string      `json:"resource_type"`
+	OldID        string      `json:"old_id"`
+	NewID        string      `json:"new_id"`
+	Tags         []tagChange `json:"tags"`
+	// Replace is set when the new ID forces the resource to be replaced.
+	Replace bool `json:"replace"`
+}
+
+// tagChange is a tag the conventions generate differently. Old or New is
+// null when only one of them generates the tag.
+type tagChange struct {
+	Key string  `json:"key"`
+	Old *string `json:"old"`
+	New *string `json:"new"`
+}
+
+// skipped is a label that could not be compared.
+type skipped struct {
+	Label  string `json:"label"`
+	Reason string `json:"reason"`
+}
+
+func runDiff(env *Env) int {
+	fs := env.flagSet("diff")
+	fs.Usage = func() {
+		fmt.Fprintln(env.Stderr, "Usage: terraform-provider-label diff [flags] <old.json> <new.json>")
+		fmt.Fprintln(env.Stderr)
+		fmt.Fprintln(env.Stderr, "Generates the labels of a spec file or plan with two convention files and")
+		fmt.Fprintln(env.Stderr, "reports the IDs and tags that change. Exits with 1 when a change forces a")
+		fmt.Fprintln(env.Stderr, "resource to be replaced.")
+		fmt.Fprintln(env.Stderr)
+		fs.PrintDefaults()
+	}
+	specFile := fs.String("spec", "", "JSON list of label data source inputs, or - for stdin")
+	planFile := fs.String("plan", "", "output of 'terraform show -json <planfile>', or - for stdin")
+	format := fs.String("format", formatHuman, "output format: human or json")
+	if code, ok := env.parse(fs); !ok {
+		return code
+	}
+
+	if fs.NArg() != 2 || (*specFile == "") == (*planFile == "") {
+		fs.Usage()
+		return ExitUsage
+	}
+	if !slices.Contains([]string{formatHuman, formatJSON}, *format) {
+		return env.errorf(ExitUsage, "unknown format %q", *format)
+	}
+
+	oldConv, err := loadConvention(fs.Arg(0), env.Vars, env.Getwd)
+	if err != nil {
+		return env.errorf(ExitUsage, "%s", err)
+	}
+	newConv, err := loadConvention(fs.Arg(1), env.Vars, env.Getwd)
+	if err != nil {
+		return env.errorf(ExitUsage, "%s", err)
+	}
+
+	var specs []labelSpec
+	report := diffReport{Skipped: []skipped{}}
+	if *specFile != "" {
+		specs, err = env.readSpecs(*specFile)
+		if err != nil {
+			return env.errorf(ExitUsage, "%s", err)
+		}
+	} else {
+		if err := complete(oldConv.cfg); err != nil {
+			return env.errorf(ExitUsage, "old convention: %s", err)
+		}
+		plan, err := env.readPlan(*planFile)
+		if err != nil {
+			return env.errorf(ExitUsage, "%s", err)
+		}
+		specs, report.Skipped = oldConv.planSpecs(plan)
+	}
+
+	changes, skips := diffLabels(oldConv, newConv, specs)
+	report.Compared = len(specs) - len(skips)
+	report.Changes = changes
+	report.Skipped = append(report.Skipped, skips...)
+
+	switch *format {
+	case formatJSON:
+		enc := json.NewEncoder(env.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(report); err != nil {
+			return env.errorf(ExitFail, "%s", err)
+		}
+	default:
+		writeDiffReport(env.Stdout, report)
+	}
+
+	if slices.ContainsFunc(report.Changes, func(c labelChange) bool { return c.Replace }) {
+		return ExitFail
+	}
+	return ExitOK
+}
+
+// readSpecs decodes a JSON list of labels from the file name, or stdin
+// for "-".
+func (env *Env) readSpecs(name string) ([]labelSpec, error) {
+	f, err := env.open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	dec := json.NewDecoder(f)
+	dec.DisallowUnknownFields()
+	var specs []labelSpec
+	if err := dec.Decode(&specs); err != nil {
+		return nil, fmt.Errorf("reading spec %s: %w", name, err)
+	}
+	return specs, nil
+}
+
+// planSpecs recovers the label inputs of the resources in the target
+// catalog that the plan keeps, by parsing their names with the convention.
+// Legacy names are parsed through the ID they are registered for. Names
+// generated with overrides are parsed as check-plan parses them, and the
+// overrides are kept as spec components.
+func (c *convention) planSpecs(plan *tfjson.Plan) ([]labelSpec, []skipped) {
+	var specs []labelSpec
+	skips := []skipped{}
+	for _, rc := range plan.ResourceChanges {
+		if rc.Mode != tfjson.ManagedResourceMode || rc.Change == nil || rc.Change.Actions.Delete() {
+			continue
+		}
+		target, ok := c.targets[rc.Type]
+		if !ok {
+			continue
+		}
+
+		values, _ := rc.Change.After.(map[string]any)
+		name, ok := target.Name(values)
+		if !ok {
+			skips = append(skips, skipped{rc.Address, "name is known after apply"})
+			continue
+		}
+
+		tags := stringMap(values["tags_all"])
+		if tags == nil {
+			tags = stringMap(values["tags"])
+		}
+		opts := c.parseOptions(target, tags)
+
+		spec := labelSpec{Address: rc.Address, Type: rc.Type, ResourceType: target.Abbreviation, Delimiter: target.Delimiter}
+		match, ok := provider.ParseNameWith(c.cfg, target.Abbreviation, name, target.Delimiter, opts)
+		if !ok && c.cfg.IsLegacyName(name) {
+			for _, id := range slices.Sorted(maps.Keys(c.cfg.LegacyNames)) {
+				if c.cfg.LegacyNames[id] != name {
+					continue
+				}
+				if match, ok = provider.ParseNameWith(c.cfg, target.Abbreviation, id, target.Delimiter, opts); ok {
+					break
+				}
+			}
+			if !ok {
+				skips = append(skips, skipped{rc.Address, fmt.Sprintf("legacy name %q is registered for a legacy_key", name)})
+				continue
+			}
+		}
+		if !ok {
+			skips = append(skips, skipped{rc.Address, fmt.Sprintf("name %q does not follow the old convention", name)})
+			continue
+		}
+		spec.Qualifier, spec.InstanceKey = match.Qualifier, match.InstanceKey
+		spec.Components = match.Overrides
+		specs = append(specs, spec)
+	}
+	return specs, skips
+}
+
+// diffLabels generates every spec with both conventions and returns the
+// labels that change, in spec order, and the specs that could not be
+// generated.
+func diffLabels(oldConv *convention, newConv *convention, specs []labelSpec) ([]labelChange, []skipped) {
+	changes := []labelChange{}
+	var skips []skipped
+	for _, spec := range specs {
+		target, known := newConv.targets[spec.Type]
+		if !known {
+			target, known = oldConv.targets[spec.Type]
+		}
+		resourceType := spec.ResourceType
+		if resourceType == "" {
+			resourceType = target.Abbreviation
+		}
+		delimiter := spec.Delimiter
+		if delimiter == "" {
+			delimiter = target.Delimiter
+		}
+		if resourceType == "" {
+			skips = append(skips, skipped{spec.describe(), "resource_type is required for types outside the target catalog"})
+			continue
+		}
+
+		generate := func(conv *convention) (string, map[string]string, error) {
+			cfg := conv.cfg.WithOverrides(spec.Components)
+			if err := complete(cfg); err != nil {
+				return "", nil, err
+			}
+			id := provider.GenerateID(cfg, resourceType, spec.Qualifier, spec.InstanceKey, delimiter)
+			tags := provider.GenerateTags(cfg, resourceType, spec.Qualifier, spec.InstanceKey, delimiter)
+			if legacy, ok := cfg.LegacyName(spec.LegacyKey, id); ok {
+				id = legacy
+			}
+			return id, tags, nil
+		}
+		oldID, oldTags, err := generate(oldConv)
+		if err != nil {
+			skips = append(skips, skipped{spec.describe(), "old convention: " + err.Error()})
+			continue
+		}
+		newID, newTags, err := generate(newConv)
+		if err != nil {
+			skips = append(skips, skipped{spec.describe(), "new convention: " + err.Error()})
+			continue
+		}
+
+		change := labelChange{
+			Label:        spec.describe(),
+			Type:         spec.Type,
+			ResourceType: resourceType,
+			OldID:        oldID,
+			NewID:        newID,
+			Tags:         []tagChange{},
+			Replace:      known && target.ReplaceOnRename && oldID != newID,
+		}
+		// Resources without tags only change by name.
+		if !known || target.Tagged {
+			change.Tags = diffTags(oldTags, newTags)
+		}
+		if oldID != newID || len(change.Tags) > 0 {
+			changes = append(changes, change)
+		}
+	}
+	return changes, skips
+}
+
+// diffTags returns the tags that differ between before and after, by key.
+func diffTags(before map[string]string, after map[string]string) []tagChange {
+	keys := slices.Sorted(maps.Keys(before))
+	for key := range after {
+		if _, ok := before[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	slices.Sort(keys)
+
+	changes := []tagChange{}
+	for _, key := range keys {
+		o, inOld := before[key]
+		n, inNew := after[key]
+		if inOld && inNew && o == n {
+			continue
+		}
+		change := tagChange{Key: key}
+		if inOld {
+			change.Old = &o
+		}
+		if inNew {
+			change.New = &n
+		}
+		changes = append(changes, change)
+	}
+	return changes
+}
+
+// writeDiffReport prints each changed label with its old and new values,
+// e.g.
+//
+//	aws_security_group.emr: id "dpl-ane2-sg-dev-emr" -> "dpl-ane2-sg-development-emr" (forces replacement)
+//	  tag Stage: "dev" -> "development"
+func writeDiffReport(w io.Writer, report diffReport) {
+	replaced := 0
+	for _, c := range report.Changes {
+		if c.OldID != c.NewID {
+			note := ""
+			if c.Replace {
+				note = " (forces replacement)"
+				replaced++
+			}
+			fmt.Fprintf(w, "%s: id %q -> %q%s\n", c.Label, c.OldID, c.NewID, note)
+		} else {
+			fmt.Fprintf(w, "%s: id %q unchanged\n", c.Label, c.OldID)
+		}
+		for _, t := range c.Tags {
+			fmt.Fprintf(w, "  tag %s: %s -> %s\n", t.Key, tagValue(t.Old), tagValue(t.New))
+		}
+	}
+	for _, s := range report.Skipped {
+		fmt.Fprintf(w, "%s: skipped, %s\n", s.Label, s.Reason)
+	}
+
+	if len(report.Changes) > 0 || len(report.Skipped) > 0 {
+		fmt.Fprintln(w)
+	}
+	fmt.Fprintf(w, "%d of %d label(s) change, %d forcing replacement, %d skipped\n", len(report.Changes), report.Compared, replaced, len(report.Skipped))
+}
+
+// tagValue formats a tag value of a tagChange.
+func tagValue(v *string) string {
+	if v == nil {
+		return "(none)"
+	}
+	return fmt.Sprintf("%q", *v)
+}
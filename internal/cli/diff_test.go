@@ -0,0 +1,118 @@
+package cli
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+const (
+	testOldConvention = `{"legacy_names": {"dpl-ane2-db-dev-orders-sales-api": "ordersprod"}}`
+	testNewConvention = `{
+		"namespace": "acme",
+		"label_order": ["tenant", "environment", "stage", "resource_type", "qualifier", "workspace", "instance_key"],
+		"legacy_names": {"dpl-ane2-db-dev-orders-sales-api": "ordersprod"}
+	}`
+)
+
+func TestDiff_Plan(t *testing.T) {
+	oldFile := writeFile(t, "old.json", testOldConvention)
+	newFile := writeFile(t, "new.json", testNewConvention)
+
+	code, stdout, stderr := runStdin(t, testVars, testPlan, "diff", "--plan", "-", oldFile, newFile)
+	if code != ExitFail {
+		t.Fatalf("exit code = %d, want %d (stderr %q)", code, ExitFail, stderr)
+	}
+	want := `aws_security_group.emr: id "dpl-ane2-sg-dev-emr-sales-api" -> "dpl-ane2-dev-sg-emr-sales-api" (forces replacement)
+  tag Name: "dpl-ane2-sg-dev-emr-sales-api" -> "dpl-ane2-dev-sg-emr-sales-api"
+  tag Namespace: (none) -> "acme"
+aws_db_instance.orders: id "ordersprod" -> "dpl-ane2-dev-db-orders-sales-api" (forces replacement)
+  tag Name: "dpl-ane2-db-dev-orders-sales-api" -> "dpl-ane2-dev-db-orders-sales-api"
+  tag Namespace: (none) -> "acme"
+aws_security_group.web: skipped, name "web-sg" does not follow the old convention
+aws_s3_bucket.logs: skipped, name is known after apply
+
+2 of 2 label(s) change, 2 forcing replacement, 2 skipped
+`
+	if stdout != want {
+		t.Errorf("stdout:\n%s\nwant:\n%s", stdout, want)
+	}
+}
+
+func TestDiff_PlanOverrides(t *testing.T) {
+	oldFile := writeFile(t, "old.json", `{}`)
+	newFile := writeFile(t, "new.json", testNewConvention)
+	plan := `{"format_version": "1.2", "resource_changes": [{
+		"address": "aws_security_group.shared", "mode": "managed", "type": "aws_security_group",
+		"change": {"actions": ["create"], "after": {"name": "dpl-ane2-sg-shared-core-sales-api", "tags": {"Stage": "shared"}}}
+	}]}`
+
+	// The stage override is kept, so only the component order changes.
+	code, stdout, stderr := runStdin(t, testVars, plan, "diff", "--plan", "-", oldFile, newFile)
+	if code != ExitFail {
+		t.Fatalf("exit code = %d, want %d (stderr %q)", code, ExitFail, stderr)
+	}
+	want := `aws_security_group.shared: id "dpl-ane2-sg-shared-core-sales-api" -> "dpl-ane2-shared-sg-core-sales-api" (forces replacement)
+  tag Name: "dpl-ane2-sg-shared-core-sales-api" -> "dpl-ane2-shared-sg-core-sales-api"
+  tag Namespace: (none) -> "acme"
+
+1 of 1 label(s) change, 1 forcing replacement, 0 skipped
+`
+	if stdout != want {
+		t.Errorf("stdout:\n%s\nwant:\n%s", stdout, want)
+	}
+}
+
+func TestDiff_Spec(t *testing.T) {
+	oldFile := writeFile(t, "old.json", `{"stage": "dev"}`)
+	newFile := writeFile(t, "new.json", `{"stage": "development"}`)
+	spec := `[
+		{"address": "aws_instance.bastion", "type": "aws_instance", "qualifier": "bastion"},
+		{"type": "aws_glue_catalog_database", "qualifier": "refined", "components": {"stage": "dev"}},
+		{"resource_type": "kms", "components": {"stage": "shared", "workspace": "core"}},
+		{"type": "aws_opensearch_domain"}
+	]`
+
+	// LABEL_STAGE would win over both convention files.
+	vars := map[string]string{"LABEL_TENANT": "dpl", "LABEL_ENVIRONMENT": "ane2", "LABEL_WORKSPACE": "sales-api"}
+	code, stdout, stderr := runStdin(t, vars, spec, "diff", "--spec", "-", "--format", "json", oldFile, newFile)
+	if code != ExitOK {
+		t.Fatalf("exit code = %d, want 0 (stdout %q, stderr %q)", code, stdout, stderr)
+	}
+
+	var report diffReport
+	if err := json.Unmarshal([]byte(stdout), &report); err != nil {
+		t.Fatalf("invalid JSON %q: %v", stdout, err)
+	}
+	if report.Compared != 3 || len(report.Changes) != 1 || len(report.Skipped) != 1 {
+		t.Fatalf("unexpected report: %+v", report)
+	}
+
+	// Renaming an instance only changes its Name tag.
+	c := report.Changes[0]
+	if c.Label != "aws_instance.bastion" || c.NewID != "dpl-ane2-ec2-development-bastion-sales-api" || c.Replace {
+		t.Errorf("unexpected change: %+v", c)
+	}
+	if len(c.Tags) != 2 || c.Tags[0].Key != "Name" || c.Tags[1].Key != "Stage" || *c.Tags[1].New != "development" {
+		t.Errorf("unexpected tag changes: %+v", c.Tags)
+	}
+	if s := report.Skipped[0]; s.Label != "type=aws_opensearch_domain" || !strings.Contains(s.Reason, "resource_type is required") {
+		t.Errorf("unexpected skip: %+v", s)
+	}
+}
+
+func TestDiff_Usage(t *testing.T) {
+	oldFile := writeFile(t, "old.json", testOldConvention)
+	for _, args := range [][]string{
+		{"diff", oldFile, oldFile},
+		{"diff", "--plan", "-", "--spec", "-", oldFile, oldFile},
+		{"diff", "--plan", "-", oldFile},
+	} {
+		if code, _, _ := run(t, testVars, args...); code != ExitUsage {
+			t.Errorf("%v: exit code = %d, want %d", args, code, ExitUsage)
+		}
+	}
+	if code, _, _ := runStdin(t, testVars, `[{"resource_typ": "sg"}]`, "diff", "--spec", "-", oldFile, oldFile); code != ExitUsage {
+		t.Errorf("unknown spec field: exit code = %d, want %d", code, ExitUsage)
+	}
+}
@@ -0,0 +1,354 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclparse"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/cloudfluent/terraform-provider-label/internal/provider"
+)
+
+func init() {
+	register("lint", "Find hard-coded names and tags in .tf files", runLint)
+}
+
+// Lint rules.
+const (
+	ruleLiteralName = "literal-name"
+	ruleLiteralTags = "literal-tags"
+)
+
+// lintFinding is a hard-coded name or tag map in a resource block.
+type lintFinding struct {
+	File       string `json:"file"`
+	Line       int    `json:"line"`
+	Column     int    `json:"column"`
+	Address    string `json:"address"`
+	Rule       string `json:"rule"`
+	Message    string `json:"message"`
+	Suggestion string `json:"suggestion,omitempty"`
+}
+
+func runLint(env *Env) int {
+	fs := env.flagSet("lint")
+	fs.Usage = func() {
+		fmt.Fprintln(env.Stderr, "Usage: terraform-provider-label lint [flags] [path ...]")
+		fmt.Fprintln(env.Stderr)
+		fmt.Fprintln(env.Stderr, "Scans .tf files (directories recursively, default .) and exits with 1 when")
+		fmt.Fprintln(env.Stderr, "a resource in the target catalog has a literal name or tag map.")
+		fmt.Fprintln(env.Stderr)
+		fs.PrintDefaults()
+	}
+	conventionFile := fs.String("convention-file", "", "convention file (default $LABEL_CONVENTION_FILE)")
+	format := fs.String("format", formatHuman, "output format: human or json")
+	if code, ok := env.parse(fs); !ok {
+		return code
+	}
+	if !slices.Contains([]string{formatHuman, formatJSON}, *format) {
+		return env.errorf(ExitUsage, "unknown format %q", *format)
+	}
+
+	conv, err := env.loadConvention(*conventionFile)
+	if err != nil {
+		return env.errorf(ExitUsage, "%s", err)
+	}
+	// Without a complete convention literals are still reported, but not
+	// recognized as convention names.
+	recognize := true
+	if err := complete(conv.cfg); err != nil {
+		fmt.Fprintf(env.Stderr, "warning: %s; names are not matched against the convention\n", err)
+		recognize = false
+	}
+
+	paths := fs.Args()
+	if len(paths) == 0 {
+		paths = []string{"."}
+	}
+	files, err := terraformFiles(paths)
+	if err != nil {
+		return env.errorf(ExitUsage, "%s", err)
+	}
+
+	parser := hclparse.NewParser()
+	findings := []lintFinding{}
+	var diags hcl.Diagnostics
+	for _, name := range files {
+		file, fileDiags := parser.ParseHCLFile(name)
+		diags = append(diags, fileDiags...)
+		if file == nil {
+			continue
+		}
+		if body, ok := file.Body.(*hclsyntax.Body); ok {
+			findings = append(findings, conv.lintBody(name, body, recognize)...)
+		}
+	}
+	if diags.HasErrors() {
+		wr := hcl.NewDiagnosticTextWriter(env.Stderr, parser.Files(), 78, false)
+		wr.WriteDiagnostics(diags)
+		return ExitUsage
+	}
+
+	switch *format {
+	case formatJSON:
+		enc := json.NewEncoder(env.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(findings); err != nil {
+			return env.errorf(ExitFail, "%s", err)
+		}
+	default:
+		writeLintFindings(env.Stdout, findings, len(files))
+	}
+
+	if len(findings) > 0 {
+		return ExitFail
+	}
+	return ExitOK
+}
+
+// terraformFiles returns the .tf files in paths, sorted. Directories are
+// walked recursively, skipping hidden ones such as .terraform.
+func terraformFiles(paths []string) ([]string, error) {
+	var files []string
+	for _, root := range paths {
+		info, err := os.Stat(root)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			files = append(files, root)
+			continue
+		}
+		err = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() && path != root && strings.HasPrefix(d.Name(), ".") {
+				return filepath.SkipDir
+			}
+			if !d.IsDir() && strings.HasSuffix(path, ".tf") {
+				files = append(files, path)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	slices.Sort(files)
+	return slices.Compact(files), nil
+}
+
+// lintBody reports literal names and tag maps on resources in the target
+// catalog.
+func (c *convention) lintBody(filename string, body *hclsyntax.Body, recognize bool) []lintFinding {
+	var findings []lintFinding
+	for _, block := range body.Blocks {
+		if block.Type != "resource" || len(block.Labels) != 2 {
+			continue
+		}
+		resourceType, resourceName := block.Labels[0], block.Labels[1]
+		target, ok := c.targets[resourceType]
+		if !ok {
+			continue
+		}
+		address := resourceType + "." + resourceName
+
+		// Literal tags tell which overrides a literal name may carry, as
+		// they do for check-plan.
+		var tags map[string]string
+		tagsAttr, hasTags := block.Body.Attributes["tags"]
+		if target.Tagged && hasTags {
+			tags, hasTags = literalTags(tagsAttr.Expr)
+		}
+		opts := c.parseOptions(target, tags)
+
+		add := func(rng hcl.Range, rule string, message string, suggestion string) {
+			findings = append(findings, lintFinding{
+				File:       filename,
+				Line:       rng.Start.Line,
+				Column:     rng.Start.Column,
+				Address:    address,
+				Rule:       rule,
+				Message:    message,
+				Suggestion: suggestion,
+			})
+		}
+
+		attr, tagName, byTag := strings.Cut(target.NameAttribute, ".")
+		if a, ok := block.Body.Attributes[attr]; ok && !byTag {
+			if name, ok := literalString(a.Expr); ok {
+				add(a.Expr.Range(), ruleLiteralName,
+					fmt.Sprintf("%s is the literal %q", attr, name),
+					c.suggestLabel(target, resourceName, attr, name, opts, recognize))
+			}
+		}
+
+		if !target.Tagged || !hasTags {
+			continue
+		}
+		name, namedByTag := tags[tagName]
+		if byTag && namedByTag {
+			add(tagsAttr.Expr.Range(), ruleLiteralName,
+				fmt.Sprintf("tag %s is the literal %q", tagName, name),
+				c.suggestLabel(target, resourceName, "tags", name, opts, recognize))
+		}
+		var keys []string
+		for _, key := range c.conventionTags() {
+			if _, ok := tags[key]; ok && !(byTag && namedByTag && key == tagName) {
+				keys = append(keys, key)
+			}
+		}
+		if len(keys) > 0 {
+			add(tagsAttr.Expr.Range(), ruleLiteralTags,
+				fmt.Sprintf("tags set %s by hand", strings.Join(keys, ", ")),
+				fmt.Sprintf("tags = data.label.%s.tags", resourceName))
+		}
+	}
+	return findings
+}
+
+// conventionTags returns the tag keys the label data source generates, in
+// the key case of the convention.
+func (c *convention) conventionTags() []string {
+	keys := []string{c.cfg.TagKey("name")}
+	for _, component := range append(slices.Clone(provider.BuiltinComponents), slices.Sorted(maps.Keys(c.cfg.Components))...) {
+		if component != "workspace" {
+			keys = append(keys, c.cfg.TagKey(component))
+		}
+	}
+	return append(keys, c.cfg.TagKey("attributes"))
+}
+
+// suggestLabel returns a data "label" block that replaces the literal name
+// of resource resourceName, and the argument that references it. Names
+// are parsed with opts, as check-plan parses them, and the overrides they
+// carry become arguments.
+func (c *convention) suggestLabel(target provider.Target, resourceName string, attr string, name string, opts provider.ParseOptions, recognize bool) string {
+	var b strings.Builder
+	arg := func(key, value string) {
+		fmt.Fprintf(&b, "  %-13s = %q\n", key, value)
+	}
+
+	fmt.Fprintf(&b, "data \"label\" %q {\n", resourceName)
+	arg("resource_type", target.Abbreviation)
+
+	parse := func(id string) bool {
+		match, ok := provider.ParseNameWith(c.cfg, target.Abbreviation, id, target.Delimiter, opts)
+		if match.Qualifier != "" {
+			arg("qualifier", match.Qualifier)
+		}
+		if match.InstanceKey != "" {
+			arg("instance_key", match.InstanceKey)
+		}
+		// Custom components have no data source argument.
+		for _, component := range provider.BuiltinComponents {
+			if v, ok := match.Overrides[component]; ok {
+				arg(component, v)
+			}
+		}
+		return ok
+	}
+
+	var note string
+	switch {
+	case !recognize:
+	case parse(name):
+		note = "# matches the convention, the name does not change"
+	case c.cfg.IsLegacyName(name):
+		// Look the legacy name up by its convention ID when the key is
+		// one, else by legacy_key.
+		key := legacyKey(c.cfg, name)
+		if !parse(key) {
+			arg("legacy_key", key)
+		}
+		note = "# a legacy name, kept through legacy_names"
+	default:
+		note = fmt.Sprintf("# does not match the convention: the name becomes %q", provider.GenerateID(c.cfg, target.Abbreviation, "", "", target.Delimiter))
+	}
+	if target.Delimiter != "" {
+		arg("delimiter", target.Delimiter)
+	}
+	b.WriteString("}\n")
+
+	ref := fmt.Sprintf("%s = data.label.%s.id", attr, resourceName)
+	if attr == "tags" {
+		ref = fmt.Sprintf("tags = data.label.%s.tags", resourceName)
+	}
+	b.WriteString(ref)
+	if note != "" {
+		b.WriteString(" " + note)
+	}
+	return b.String()
+}
+
+// legacyKey returns the first legacy_names key, in sorted order, that maps
+// to name.
+func legacyKey(cfg *provider.LabelConfig, name string) string {
+	for _, key := range slices.Sorted(maps.Keys(cfg.LegacyNames)) {
+		if cfg.LegacyNames[key] == name {
+			return key
+		}
+	}
+	return ""
+}
+
+// literalString returns the value of expr when it is a string that does
+// not reference anything.
+func literalString(expr hcl.Expression) (string, bool) {
+	if len(expr.Variables()) > 0 {
+		return "", false
+	}
+	if _, ok := expr.(*hclsyntax.FunctionCallExpr); ok {
+		return "", false
+	}
+	v, diags := expr.Value(nil)
+	if diags.HasErrors() || v.IsNull() || !v.IsKnown() || v.Type() != cty.String {
+		return "", false
+	}
+	return v.AsString(), true
+}
+
+// literalTags returns the string values of expr when it is an object
+// constructor. Values that reference something are skipped; keys must be
+// literal.
+func literalTags(expr hcl.Expression) (map[string]string, bool) {
+	obj, ok := expr.(*hclsyntax.ObjectConsExpr)
+	if !ok {
+		return nil, false
+	}
+	tags := map[string]string{}
+	for _, item := range obj.Items {
+		key, diags := item.KeyExpr.Value(nil)
+		if diags.HasErrors() || key.IsNull() || !key.IsKnown() || key.Type() != cty.String {
+			continue
+		}
+		if value, ok := literalString(item.ValueExpr); ok {
+			tags[key.AsString()] = value
+		}
+	}
+	return tags, true
+}
+
+func writeLintFindings(w io.Writer, findings []lintFinding, files int) {
+	for _, f := range findings {
+		fmt.Fprintf(w, "%s:%d:%d: %s: %s\n", f.File, f.Line, f.Column, f.Address, f.Message)
+		if f.Suggestion != "" {
+			for _, line := range strings.Split(f.Suggestion, "\n") {
+				fmt.Fprintf(w, "    %s\n", line)
+			}
+		}
+		fmt.Fprintln(w)
+	}
+	fmt.Fprintf(w, "%d finding(s) in %d file(s)\n", len(findings), files)
+}
@@ -0,0 +1,197 @@
+package cli
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testConfig = `
+data "label" "app" {
+  resource_type = "sg"
+}
+
+resource "aws_security_group" "emr" {
+  name = "dpl-ane2-sg-dev-emr-sales-api"
+  tags = {
+    Name  = "dpl-ane2-sg-dev-emr-sales-api"
+    Owner = "data-team"
+  }
+}
+
+resource "aws_security_group" "web" {
+  name = "web-sg"
+  tags = data.label.app.tags
+}
+
+resource "aws_security_group" "app" {
+  name = data.label.app.id
+  tags = merge(data.label.app.tags, { Owner = "app" })
+}
+
+resource "aws_db_instance" "orders" {
+  identifier = "ordersprod"
+}
+
+resource "aws_vpc" "main" {
+  tags = {
+    Name  = "dpl-ane2-vpc-dev-sales-api"
+    Stage = "dev"
+  }
+}
+
+resource "aws_glue_catalog_database" "refined" {
+  name = "dpl_ane2_db_dev_refined_sales_api"
+}
+
+resource "random_pet" "x" {
+  prefix = "literal"
+}
+`
+
+func TestLint(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "main.tf"), []byte(testConfig), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	// Hidden directories such as .terraform are skipped.
+	if err := os.MkdirAll(filepath.Join(dir, ".terraform"), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, ".terraform", "x.tf"), []byte(`resource "aws_sqs_queue" "q" { name = "q" }`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	convention := writeFile(t, "convention.json", `{"legacy_names": {"dpl-ane2-db-dev-orders-sales-api": "ordersprod"}}`)
+	vars := map[string]string{"LABEL_CONVENTION_FILE": convention}
+	for k, v := range testVars {
+		vars[k] = v
+	}
+
+	code, stdout, stderr := run(t, vars, "lint", "--format", "json", dir)
+	if code != ExitFail {
+		t.Fatalf("exit code = %d, want %d (stderr %q)", code, ExitFail, stderr)
+	}
+
+	var findings []lintFinding
+	if err := json.Unmarshal([]byte(stdout), &findings); err != nil {
+		t.Fatalf("invalid JSON %q: %v", stdout, err)
+	}
+
+	var got []string
+	for _, f := range findings {
+		got = append(got, f.Address+" "+f.Rule)
+	}
+	want := []string{
+		"aws_security_group.emr literal-name",
+		"aws_security_group.emr literal-tags",
+		"aws_security_group.web literal-name",
+		"aws_db_instance.orders literal-name",
+		"aws_vpc.main literal-name",
+		"aws_vpc.main literal-tags",
+		"aws_glue_catalog_database.refined literal-name",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("findings:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	suggestions := map[string]string{
+		"aws_security_group.emr": `data "label" "emr" {
+  resource_type = "sg"
+  qualifier     = "emr"
+}
+name = data.label.emr.id # matches the convention, the name does not change`,
+		"aws_security_group.web": `data "label" "web" {
+  resource_type = "sg"
+}
+name = data.label.web.id # does not match the convention: the name becomes "dpl-ane2-sg-dev-sales-api"`,
+		"aws_db_instance.orders": `data "label" "orders" {
+  resource_type = "db"
+  qualifier     = "orders"
+}
+identifier = data.label.orders.id # a legacy name, kept through legacy_names`,
+		"aws_vpc.main": `data "label" "main" {
+  resource_type = "vpc"
+}
+tags = data.label.main.tags # matches the convention, the name does not change`,
+		"aws_glue_catalog_database.refined": `data "label" "refined" {
+  resource_type = "db"
+  qualifier     = "refined"
+  delimiter     = "_"
+}
+name = data.label.refined.id # matches the convention, the name does not change`,
+	}
+	for _, f := range findings {
+		if want, ok := suggestions[f.Address]; ok && f.Rule == ruleLiteralName && f.Suggestion != want {
+			t.Errorf("%s suggestion:\n%s\nwant:\n%s", f.Address, f.Suggestion, want)
+		}
+	}
+	if f := findings[0]; f.Line != 7 || f.Column != 10 || !strings.HasSuffix(f.File, "main.tf") {
+		t.Errorf("first finding at %s:%d:%d, want main.tf:7:10", f.File, f.Line, f.Column)
+	}
+}
+
+func TestLint_Overrides(t *testing.T) {
+	path := writeFile(t, "main.tf", `
+resource "aws_security_group" "shared" {
+  name = "dpl-ane2-sg-shared-core-sales-api"
+  tags = {
+    Stage      = "shared"
+    CostCenter = "data"
+  }
+}
+`)
+	vars := map[string]string{"LABEL_COMPONENT_COST_CENTER": "data"}
+	for k, v := range testVars {
+		vars[k] = v
+	}
+
+	code, stdout, stderr := run(t, vars, "lint", "--format", "json", path)
+	if code != ExitFail {
+		t.Fatalf("exit code = %d, want %d (stderr %q)", code, ExitFail, stderr)
+	}
+	var findings []lintFinding
+	if err := json.Unmarshal([]byte(stdout), &findings); err != nil {
+		t.Fatalf("invalid JSON %q: %v", stdout, err)
+	}
+	if len(findings) != 2 {
+		t.Fatalf("findings: %+v", findings)
+	}
+
+	// The Stage tag lets the name carry the override, as in check-plan.
+	want := `data "label" "shared" {
+  resource_type = "sg"
+  qualifier     = "core"
+  stage         = "shared"
+}
+name = data.label.shared.id # matches the convention, the name does not change`
+	if f := findings[0]; f.Rule != ruleLiteralName || f.Suggestion != want {
+		t.Errorf("suggestion:\n%s\nwant:\n%s", f.Suggestion, want)
+	}
+	// Custom components are convention tags too.
+	if f := findings[1]; f.Rule != ruleLiteralTags || f.Message != "tags set Stage, CostCenter by hand" {
+		t.Errorf("unexpected finding: %+v", f)
+	}
+}
+
+func TestLint_Clean(t *testing.T) {
+	path := writeFile(t, "main.tf", `
+resource "aws_security_group" "app" {
+  name = data.label.app.id
+  tags = data.label.app.tags
+}
+`)
+	code, stdout, stderr := run(t, testVars, "lint", path)
+	if code != ExitOK || !strings.Contains(stdout, "0 finding(s) in 1 file(s)") {
+		t.Errorf("exit code = %d, stdout %q, stderr %q", code, stdout, stderr)
+	}
+}
+
+func TestLint_SyntaxError(t *testing.T) {
+	path := writeFile(t, "main.tf", `resource "aws_security_group" "app" {`)
+	if code, _, _ := run(t, testVars, "lint", path); code != ExitUsage {
+		t.Errorf("exit code = %d, want %d", code, ExitUsage)
+	}
+}
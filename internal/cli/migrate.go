@@ -0,0 +1,437 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func init() {
+	register("migrate-null-label", "Rewrite cloudposse/label/null modules into label data sources", runMigrateNullLabel)
+}
+
+// nullLabelArguments maps null-label module arguments to label data source
+// arguments. attributes is handled separately.
+var nullLabelArguments = map[string]string{
+	"name":        "qualifier",
+	"namespace":   "namespace",
+	"tenant":      "tenant",
+	"environment": "environment",
+	"stage":       "stage",
+	"delimiter":   "delimiter",
+}
+
+// metaArguments are the module meta-arguments data sources support too.
+// They are copied as they are.
+var metaArguments = []string{"count", "for_each", "depends_on"}
+
+// labelArgumentOrder is the order of arguments in migrated blocks.
+var labelArgumentOrder = []string{"count", "for_each", "resource_type", "context_json", "qualifier", "instance_key", "delimiter", "tenant", "environment", "stage", "namespace", "depends_on"}
+
+// nullLabelOutputs maps null-label module outputs to data source attributes.
+var nullLabelOutputs = map[string]string{
+	"id":      "id",
+	"id_full": "id",
+	"tags":    "tags",
+	"context": "null_label_context",
+}
+
+// migrationNote is a construct that could not be translated.
+type migrationNote struct {
+	pos     hcl.Pos
+	file    string
+	message string
+}
+
+func (n migrationNote) String() string {
+	return fmt.Sprintf("%s:%d:%d: %s", n.file, n.pos.Line, n.pos.Column, n.message)
+}
+
+func runMigrateNullLabel(env *Env) int {
+	fs := env.flagSet("migrate-null-label")
+	fs.Usage = func() {
+		fmt.Fprintln(env.Stderr, "Usage: terraform-provider-label migrate-null-label [flags] [path ...]")
+		fmt.Fprintln(env.Stderr)
+		fmt.Fprintln(env.Stderr, "Rewrites module blocks with source cloudposse/label/null into data \"label\"")
+		fmt.Fprintln(env.Stderr, "blocks and module.<name>.id/tags references into data.label.<name>.id/tags.")
+		fmt.Fprintln(env.Stderr, "Prints the rewritten files unless -write is set, and exits with 1 when")
+		fmt.Fprintln(env.Stderr, "something could not be translated.")
+		fmt.Fprintln(env.Stderr)
+		fs.PrintDefaults()
+	}
+	write := fs.Bool("write", false, "rewrite the files in place")
+	resourceType := fs.String("resource-type", "", "resource_type of the migrated blocks (default: a TODO placeholder; required with -write)")
+	if code, ok := env.parse(fs); !ok {
+		return code
+	}
+	if *write && *resourceType == "" {
+		return env.errorf(ExitUsage, "-write requires --resource-type, so that no TODO placeholder is written")
+	}
+
+	paths := fs.Args()
+	if len(paths) == 0 {
+		paths = []string{"."}
+	}
+	files, err := terraformFiles(paths)
+	if err != nil {
+		return env.errorf(ExitUsage, "%s", err)
+	}
+
+	// Module names are scoped to a directory, so migrate one at a time.
+	byDir := map[string][]string{}
+	for _, name := range files {
+		dir := filepath.Dir(name)
+		byDir[dir] = append(byDir[dir], name)
+	}
+
+	var notes []migrationNote
+	changed, migrated := 0, 0
+	for _, dir := range slices.Sorted(maps.Keys(byDir)) {
+		m := &migration{resourceType: *resourceType}
+		out, err := m.migrate(byDir[dir])
+		if err != nil {
+			return env.errorf(ExitUsage, "%s", err)
+		}
+		notes = append(notes, m.notes...)
+		migrated += len(m.modules)
+
+		for _, name := range byDir[dir] {
+			content, ok := out[name]
+			if !ok {
+				continue
+			}
+			changed++
+			if *write {
+				if err := os.WriteFile(name, content, 0o644); err != nil {
+					return env.errorf(ExitFail, "%s", err)
+				}
+				fmt.Fprintf(env.Stdout, "rewrote %s\n", name)
+				continue
+			}
+			fmt.Fprintf(env.Stdout, "# %s\n%s\n", name, content)
+		}
+	}
+
+	for _, n := range notes {
+		fmt.Fprintln(env.Stderr, n)
+	}
+	if migrated > 0 {
+		fmt.Fprintln(env.Stderr, `warning: the data sources generate null-label IDs only when the provider sets compat = "null-label"; otherwise the IDs follow the provider convention, which adds tenant, resource_type and workspace, and change`)
+	}
+	fmt.Fprintf(env.Stderr, "%d file(s) changed, %d construct(s) to migrate by hand\n", changed, len(notes))
+	if len(notes) > 0 {
+		return ExitFail
+	}
+	return ExitOK
+}
+
+// migration rewrites the null-label modules of one directory.
+type migration struct {
+	resourceType string
+	// modules are the names of the migrated module blocks.
+	modules map[string]bool
+	notes   []migrationNote
+}
+
+func (m *migration) note(file string, pos hcl.Pos, format string, args ...any) {
+	m.notes = append(m.notes, migrationNote{pos: pos, file: file, message: fmt.Sprintf(format, args...)})
+}
+
+// migrate returns the new content of the files that change.
+func (m *migration) migrate(files []string) (map[string][]byte, error) {
+	type parsed struct {
+		src    []byte
+		syntax *hclsyntax.Body
+		write  *hclwrite.File
+	}
+	sources := map[string]parsed{}
+	for _, name := range files {
+		src, err := os.ReadFile(name)
+		if err != nil {
+			return nil, err
+		}
+		syntaxFile, diags := hclsyntax.ParseConfig(src, name, hcl.InitialPos)
+		if diags.HasErrors() {
+			return nil, diags
+		}
+		writeFile, diags := hclwrite.ParseConfig(src, name, hcl.InitialPos)
+		if diags.HasErrors() {
+			return nil, diags
+		}
+		sources[name] = parsed{src, syntaxFile.Body.(*hclsyntax.Body), writeFile}
+	}
+
+	m.modules = map[string]bool{}
+	for _, name := range files {
+		p := sources[name]
+		for _, block := range p.syntax.Blocks {
+			if block.Type == "module" && len(block.Labels) == 1 && isNullLabel(block.Body) {
+				m.modules[block.Labels[0]] = true
+				m.convertBlock(name, block, p.write.Body().FirstMatchingBlock("module", block.Labels))
+			}
+		}
+	}
+	if len(m.modules) == 0 {
+		return map[string][]byte{}, nil
+	}
+
+	out := map[string][]byte{}
+	for _, name := range files {
+		p := sources[name]
+		m.checkReferences(name, p.syntax)
+		m.renameReferences(p.write.Body())
+		if content := hclwrite.Format(p.write.Bytes()); !bytes.Equal(content, p.src) {
+			out[name] = content
+		}
+	}
+	return out, nil
+}
+
+// isNullLabel reports whether a module block calls cloudposse/label/null.
+func isNullLabel(body *hclsyntax.Body) bool {
+	attr, ok := body.Attributes["source"]
+	if !ok {
+		return false
+	}
+	source, ok := literalString(attr.Expr)
+	return ok && (strings.HasPrefix(source, "cloudposse/label/null") || strings.Contains(source, "terraform-null-label"))
+}
+
+// convertBlock turns the module block into a data "label" block.
+func (m *migration) convertBlock(file string, block *hclsyntax.Block, wb *hclwrite.Block) {
+	name := block.Labels[0]
+	body := wb.Body()
+
+	args := map[string]hclwrite.Tokens{}
+	resourceType := m.resourceType
+	if resourceType == "" {
+		resourceType = "TODO"
+		m.note(file, block.DefRange().Start, "module.%s: set resource_type, null-label has no equivalent", name)
+	}
+	args["resource_type"] = hclwrite.TokensForValue(cty.StringVal(resourceType))
+
+	for argName, attr := range block.Body.Attributes {
+		tokens := body.GetAttribute(argName).Expr().BuildTokens(nil)
+		switch to, ok := nullLabelArguments[argName]; {
+		case argName == "source" || argName == "version":
+		case ok:
+			args[to] = tokens
+		case slices.Contains(metaArguments, argName):
+			args[argName] = tokens
+		case argName == "context":
+			// module.x.context becomes data.label.x.null_label_context
+			// with the other references when x is migrated.
+			args["context_json"] = hclwrite.TokensForFunctionCall("jsonencode", tokens)
+		case argName == "attributes":
+			if key, ok := m.instanceKey(block, attr); ok {
+				args["instance_key"] = hclwrite.TokensForValue(cty.StringVal(key))
+			} else {
+				m.note(file, attr.SrcRange.Start, "module.%s: attributes must be a list of literal strings to become instance_key", name)
+			}
+		default:
+			m.note(file, attr.SrcRange.Start, "module.%s: argument %s has no label data source equivalent", name, argName)
+		}
+	}
+	for _, nested := range block.Body.Blocks {
+		m.note(file, nested.DefRange().Start, "module.%s: block %s has no label data source equivalent", name, nested.Type)
+	}
+
+	// Clear alone leaves the attributes registered with the body.
+	for argName := range body.Attributes() {
+		body.RemoveAttribute(argName)
+	}
+	for _, nested := range body.Blocks() {
+		body.RemoveBlock(nested)
+	}
+	body.Clear()
+	body.AppendNewline()
+	for _, argName := range labelArgumentOrder {
+		if tokens, ok := args[argName]; ok {
+			body.SetAttributeRaw(argName, tokens)
+		}
+	}
+	wb.SetType("data")
+	wb.SetLabels([]string{"label", name})
+}
+
+// instanceKey joins literal null-label attributes with the block's
+// delimiter, e.g. ["public", "a"] → "public-a".
+func (m *migration) instanceKey(block *hclsyntax.Block, attr *hclsyntax.Attribute) (string, bool) {
+	delimiter := "-"
+	if d, ok := block.Body.Attributes["delimiter"]; ok {
+		if delimiter, ok = literalString(d.Expr); !ok {
+			return "", false
+		}
+	}
+
+	if len(attr.Expr.Variables()) > 0 {
+		return "", false
+	}
+	v, diags := attr.Expr.Value(nil)
+	if diags.HasErrors() || !v.IsKnown() || v.IsNull() || !(v.Type().IsTupleType() || v.Type().IsListType()) {
+		return "", false
+	}
+	var parts []string
+	for it := v.ElementIterator(); it.Next(); {
+		_, elem := it.Element()
+		if elem.IsNull() || elem.Type() != cty.String {
+			return "", false
+		}
+		parts = append(parts, elem.AsString())
+	}
+	return strings.Join(parts, delimiter), true
+}
+
+// checkReferences notes references to migrated modules that have no data
+// source equivalent, such as module.x.normalized_context.
+func (m *migration) checkReferences(file string, body *hclsyntax.Body) {
+	for _, block := range body.Blocks {
+		// Arguments of migrated blocks are noted by convertBlock.
+		if block.Type == "module" && len(block.Labels) == 1 && m.modules[block.Labels[0]] {
+			continue
+		}
+		m.checkBlockReferences(file, block)
+	}
+}
+
+func (m *migration) checkBlockReferences(file string, block *hclsyntax.Block) {
+	// References such as module.x[each.key].id are parsed as module.x
+	// followed by an index and the output, and depends_on may name the
+	// module itself; both are renamed.
+	renamed := map[*hclsyntax.ScopeTraversalExpr]bool{}
+	hclsyntax.VisitAll(block, func(node hclsyntax.Node) hcl.Diagnostics {
+		switch node := node.(type) {
+		case *hclsyntax.RelativeTraversalExpr:
+			index, ok := node.Source.(*hclsyntax.IndexExpr)
+			if !ok {
+				return nil
+			}
+			expr, ok := index.Collection.(*hclsyntax.ScopeTraversalExpr)
+			if !ok {
+				return nil
+			}
+			if out, ok := node.Traversal[0].(hcl.TraverseAttr); ok && nullLabelOutputs[out.Name] != "" {
+				renamed[expr] = true
+			}
+		case *hclsyntax.Attribute:
+			if node.Name != "depends_on" {
+				return nil
+			}
+			hclsyntax.VisitAll(node.Expr, func(n hclsyntax.Node) hcl.Diagnostics {
+				if expr, ok := n.(*hclsyntax.ScopeTraversalExpr); ok && len(expr.Traversal) == 2 {
+					renamed[expr] = true
+				}
+				return nil
+			})
+		}
+		return nil
+	})
+
+	hclsyntax.VisitAll(block, func(node hclsyntax.Node) hcl.Diagnostics {
+		expr, ok := node.(*hclsyntax.ScopeTraversalExpr)
+		if !ok || expr.Traversal.RootName() != "module" || len(expr.Traversal) < 2 || renamed[expr] {
+			return nil
+		}
+		step, ok := expr.Traversal[1].(hcl.TraverseAttr)
+		if !ok || !m.modules[step.Name] {
+			return nil
+		}
+		if out, ok := outputStep(expr.Traversal[2:]); ok && nullLabelOutputs[out] != "" {
+			return nil
+		}
+		m.note(file, expr.SrcRange.Start, "module.%s: reference %s has no label data source equivalent", step.Name, traversalString(expr.Traversal))
+		return nil
+	})
+}
+
+// outputStep returns the output a module traversal reads after any
+// instance keys, e.g. id for module.x["a"].id.
+func outputStep(steps hcl.Traversal) (string, bool) {
+	for _, step := range steps {
+		switch step := step.(type) {
+		case hcl.TraverseIndex:
+		case hcl.TraverseAttr:
+			return step.Name, true
+		default:
+			return "", false
+		}
+	}
+	return "", false
+}
+
+// renameReferences rewrites module.x.id, module.x[key].tags,
+// module.x.context and module.x in depends_on in every attribute of body
+// and its nested blocks. hclwrite can only rename to a traversal of the
+// same length, so the tokens are edited in place.
+func (m *migration) renameReferences(body *hclwrite.Body) {
+	for attrName, attr := range body.Attributes() {
+		tokens := attr.Expr().BuildTokens(nil)
+		for i := 0; i+2 < len(tokens); i++ {
+			if !m.isModuleReference(tokens, i) {
+				continue
+			}
+			// Skip the instance keys, e.g. [each.key].
+			j := i + 3
+			for j < len(tokens) && tokens[j].Type == hclsyntax.TokenOBrack {
+				depth := 0
+				for ; j < len(tokens); j++ {
+					if tokens[j].Type == hclsyntax.TokenOBrack {
+						depth++
+					} else if tokens[j].Type == hclsyntax.TokenCBrack {
+						if depth--; depth == 0 {
+							j++
+							break
+						}
+					}
+				}
+			}
+			if j+1 < len(tokens) && tokens[j].Type == hclsyntax.TokenDot && tokens[j+1].Type == hclsyntax.TokenIdent {
+				if to, ok := nullLabelOutputs[string(tokens[j+1].Bytes)]; ok {
+					tokens[i].Bytes = []byte("data.label")
+					tokens[j+1].Bytes = []byte(to)
+				}
+				continue
+			}
+			if attrName == "depends_on" {
+				tokens[i].Bytes = []byte("data.label")
+			}
+		}
+	}
+	for _, block := range body.Blocks() {
+		m.renameReferences(block.Body())
+	}
+}
+
+// isModuleReference reports whether tokens[i:] start with module.<x> for a
+// migrated module x.
+func (m *migration) isModuleReference(tokens hclwrite.Tokens, i int) bool {
+	if i > 0 && tokens[i-1].Type == hclsyntax.TokenDot {
+		return false
+	}
+	return tokens[i].Type == hclsyntax.TokenIdent && string(tokens[i].Bytes) == "module" &&
+		tokens[i+1].Type == hclsyntax.TokenDot &&
+		tokens[i+2].Type == hclsyntax.TokenIdent && m.modules[string(tokens[i+2].Bytes)]
+}
+
+// traversalString renders the attribute steps of a traversal,
+// e.g. module.label.context.
+func traversalString(t hcl.Traversal) string {
+	parts := []string{t.RootName()}
+	for _, step := range t[1:] {
+		attr, ok := step.(hcl.TraverseAttr)
+		if !ok {
+			break
+		}
+		parts = append(parts, attr.Name)
+	}
+	return strings.Join(parts, ".")
+}
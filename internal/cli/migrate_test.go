@@ -0,0 +1,198 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testNullLabel = `module "label" {
+  source     = "cloudposse/label/null"
+  version    = "0.25.0"
+  namespace  = "dpl"
+  stage      = var.stage
+  name       = "emr"
+  attributes = ["public", "a"]
+  context    = module.this.context
+}
+
+module "db" {
+  source      = "cloudposse/label/null"
+  name        = "db"
+  label_order = ["name"]
+  context     = module.label.context
+}
+
+resource "aws_security_group" "emr" {
+  name = module.label.id
+  tags = module.label.tags
+}
+
+output "context" {
+  value = module.label.normalized_context
+}
+
+output "name" {
+  value = "${module.label.id}-extra"
+}
+`
+
+const testMigrated = `data "label" "label" {
+  resource_type = "sg"
+  context_json  = jsonencode(module.this.context)
+  qualifier     = "emr"
+  instance_key  = "public-a"
+  stage         = var.stage
+  namespace     = "dpl"
+}
+
+data "label" "db" {
+  resource_type = "sg"
+  context_json  = jsonencode(data.label.label.null_label_context)
+  qualifier     = "db"
+}
+
+resource "aws_security_group" "emr" {
+  name = data.label.label.id
+  tags = data.label.label.tags
+}
+
+output "context" {
+  value = module.label.normalized_context
+}
+
+output "name" {
+  value = "${data.label.label.id}-extra"
+}
+`
+
+func TestMigrateNullLabel(t *testing.T) {
+	path := writeFile(t, "main.tf", testNullLabel)
+
+	code, stdout, stderr := run(t, testVars, "migrate-null-label", "-resource-type", "sg", path)
+	if code != ExitFail {
+		t.Fatalf("exit code = %d, want %d (stderr %q)", code, ExitFail, stderr)
+	}
+	if want := "# " + path + "\n" + testMigrated + "\n"; stdout != want {
+		t.Errorf("stdout:\n%s\nwant:\n%s", stdout, want)
+	}
+	for _, want := range []string{
+		"main.tf:14:3: module.db: argument label_order has no label data source equivalent",
+		"main.tf:24:11: module.label: reference module.label.normalized_context has no label data source equivalent",
+		"1 file(s) changed, 2 construct(s) to migrate by hand",
+	} {
+		if !strings.Contains(stderr, want) {
+			t.Errorf("stderr missing %q:\n%s", want, stderr)
+		}
+	}
+
+	// Without -write the file is untouched.
+	if content, _ := os.ReadFile(path); string(content) != testNullLabel {
+		t.Errorf("file changed without -write:\n%s", content)
+	}
+}
+
+func TestMigrateNullLabel_Write(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "main.tf")
+	src := `module "label" {
+  source    = "git::https://github.com/cloudposse/terraform-null-label.git?ref=0.25.0"
+  name      = "api"
+  delimiter = "_"
+}
+
+module "vpc" {
+  source = "terraform-aws-modules/vpc/aws"
+  name   = module.label.id_full
+}
+`
+	if err := os.WriteFile(path, []byte(src), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	// Without --resource-type the files would get a TODO placeholder.
+	code, _, stderr := run(t, testVars, "migrate-null-label", "-write", dir)
+	if code != ExitUsage || !strings.Contains(stderr, "-write requires --resource-type") {
+		t.Fatalf("without --resource-type: exit code = %d, stderr %q", code, stderr)
+	}
+
+	code, stdout, stderr := run(t, testVars, "migrate-null-label", "-write", "--resource-type", "vpc", dir)
+	if code != ExitOK || !strings.Contains(stdout, "rewrote "+path) {
+		t.Fatalf("exit code = %d, stdout %q, stderr %q", code, stdout, stderr)
+	}
+	if !strings.Contains(stderr, `compat = "null-label"`) {
+		t.Errorf("stderr missing compat warning:\n%s", stderr)
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `data "label" "label" {
+  resource_type = "vpc"
+  qualifier     = "api"
+  delimiter     = "_"
+}
+
+module "vpc" {
+  source = "terraform-aws-modules/vpc/aws"
+  name   = data.label.label.id
+}
+`
+	if string(content) != want {
+		t.Errorf("rewritten file:\n%s\nwant:\n%s", content, want)
+	}
+}
+
+func TestMigrateNullLabel_MetaArguments(t *testing.T) {
+	path := writeFile(t, "main.tf", `module "label" {
+  source     = "cloudposse/label/null"
+  for_each   = toset(["a", "b"])
+  name       = each.key
+  depends_on = [module.vpc]
+}
+
+resource "aws_security_group" "emr" {
+  for_each   = toset(["a", "b"])
+  name       = module.label[each.key].id
+  tags       = module.label["a"].tags
+  depends_on = [module.label]
+}
+`)
+	code, stdout, stderr := run(t, testVars, "migrate-null-label", "--resource-type", "sg", path)
+	if code != ExitOK {
+		t.Fatalf("exit code = %d, stderr %q", code, stderr)
+	}
+	want := `data "label" "label" {
+  for_each      = toset(["a", "b"])
+  resource_type = "sg"
+  qualifier     = each.key
+  depends_on    = [module.vpc]
+}
+
+resource "aws_security_group" "emr" {
+  for_each   = toset(["a", "b"])
+  name       = data.label.label[each.key].id
+  tags       = data.label.label["a"].tags
+  depends_on = [data.label.label]
+}
+`
+	if !strings.Contains(stdout, want) {
+		t.Errorf("stdout:\n%s\nwant:\n%s", stdout, want)
+	}
+	if strings.Contains(stderr, "no label data source equivalent") {
+		t.Errorf("unexpected notes:\n%s", stderr)
+	}
+}
+
+func TestMigrateNullLabel_Nothing(t *testing.T) {
+	path := writeFile(t, "main.tf", `module "vpc" {
+  source = "terraform-aws-modules/vpc/aws"
+}
+`)
+	code, stdout, stderr := run(t, testVars, "migrate-null-label", path)
+	if code != ExitOK || stdout != "" || !strings.Contains(stderr, "0 file(s) changed") {
+		t.Errorf("exit code = %d, stdout %q, stderr %q", code, stdout, stderr)
+	}
+}
@@ -0,0 +1,183 @@
+package cli
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/cloudfluent/terraform-provider-label/internal/provider"
+)
+
+func init() {
+	register("name", "Print the ID and tags the label data source generates", runName)
+}
+
+// Output formats of the name command.
+const (
+	formatText  = "text"
+	formatJSON  = "json"
+	formatShell = "shell"
+)
+
+// label is the output of the name command.
+type label struct {
+	ID         string            `json:"id"`
+	Tags       map[string]string `json:"tags"`
+	Attributes []string          `json:"attributes"`
+	IsLegacy   bool              `json:"is_legacy"`
+	// Sources maps each provider-level component to where its value came
+	// from, like the sources attribute of the data source.
+	Sources map[string]string `json:"sources"`
+}
+
+// labelFlags are the label data source arguments as flags.
+type labelFlags struct {
+	resourceType string
+	qualifier    string
+	instanceKey  string
+	delimiter    string
+	legacyKey    string
+	overrides    map[string]*string
+}
+
+func (f *labelFlags) register(fs *flag.FlagSet) {
+	fs.StringVar(&f.resourceType, "resource-type", "", "resource type abbreviation, e.g. sg (required)")
+	fs.StringVar(&f.qualifier, "qualifier", "", "qualifier segment")
+	fs.StringVar(&f.instanceKey, "instance-key", "", "instance key")
+	fs.StringVar(&f.delimiter, "delimiter", "", "override the convention delimiter")
+	fs.StringVar(&f.legacyKey, "legacy-key", "", "key to look up in legacy_names")
+	f.overrides = map[string]*string{}
+	for _, name := range provider.BuiltinComponents {
+		f.overrides[name] = fs.String(name, "", "override the "+name+" component")
+	}
+}
+
+// apply returns cfg with the component overrides that were set on the
+// command line.
+func (f *labelFlags) apply(fs *flag.FlagSet, cfg *provider.LabelConfig) *provider.LabelConfig {
+	overrides := map[string]string{}
+	fs.Visit(func(fl *flag.Flag) {
+		if v, ok := f.overrides[fl.Name]; ok {
+			overrides[fl.Name] = *v
+		}
+	})
+	out := cfg.WithOverrides(overrides)
+	if out.Sources == nil {
+		out.Sources = map[string]string{}
+	}
+	for name := range overrides {
+		out.Sources[name] = "flag --" + name
+	}
+	return out
+}
+
+func runName(env *Env) int {
+	fs := env.flagSet("name")
+	var lf labelFlags
+	lf.register(fs)
+	conventionFile := fs.String("convention-file", "", "convention file (default $"+provider.ConventionFileEnvVar+")")
+	format := fs.String("format", formatText, "output format: text, json or shell")
+	if code, ok := env.parse(fs); !ok {
+		return code
+	}
+
+	if lf.resourceType == "" {
+		return env.errorf(ExitUsage, "--resource-type is required")
+	}
+	if !slices.Contains([]string{formatText, formatJSON, formatShell}, *format) {
+		return env.errorf(ExitUsage, "unknown format %q", *format)
+	}
+
+	conv, err := env.loadConvention(*conventionFile)
+	if err != nil {
+		return env.errorf(ExitUsage, "%s", err)
+	}
+	cfg := lf.apply(fs, conv.cfg)
+	if err := complete(cfg); err != nil {
+		return env.errorf(ExitUsage, "%s", err)
+	}
+
+	out := generateLabel(cfg, lf)
+	if !out.IsLegacy && (cfg.AmbiguityCheck == provider.CheckWarn || cfg.AmbiguityCheck == provider.CheckError) {
+		if ambiguous := provider.AmbiguousComponents(cfg, lf.resourceType, lf.qualifier, lf.instanceKey, lf.delimiter); len(ambiguous) > 0 {
+			for _, a := range ambiguous {
+				fmt.Fprintf(env.Stderr, "warning: ID %q is ambiguous: %s = %q contains the delimiter\n", out.ID, a.Component, a.Value)
+			}
+			if cfg.AmbiguityCheck == provider.CheckError {
+				return ExitFail
+			}
+		}
+	}
+
+	switch *format {
+	case formatJSON:
+		enc := json.NewEncoder(env.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(out); err != nil {
+			return env.errorf(ExitFail, "%s", err)
+		}
+	case formatShell:
+		writeShell(env.Stdout, out)
+	default:
+		fmt.Fprintln(env.Stdout, out.ID)
+	}
+	return ExitOK
+}
+
+// generateLabel mirrors the label data source: tags carry the convention
+// name even when the ID is a legacy name.
+func generateLabel(cfg *provider.LabelConfig, lf labelFlags) label {
+	id := provider.GenerateID(cfg, lf.resourceType, lf.qualifier, lf.instanceKey, lf.delimiter)
+	tags := provider.GenerateTags(cfg, lf.resourceType, lf.qualifier, lf.instanceKey, lf.delimiter)
+	attributes := provider.GenerateAttributes(cfg, lf.resourceType, lf.qualifier, lf.instanceKey)
+	if attributes == nil {
+		attributes = []string{}
+	}
+
+	out := label{ID: id, Tags: tags, Attributes: attributes, Sources: cfg.ComponentSources()}
+	if legacy, ok := cfg.LegacyName(lf.legacyKey, id); ok {
+		out.ID = legacy
+		out.IsLegacy = true
+	}
+	return out
+}
+
+// writeShell prints out as export statements: LABEL_ID and one
+// LABEL_TAG_<KEY> per tag, e.g. LABEL_TAG_COST_CENTER.
+func writeShell(w io.Writer, out label) {
+	fmt.Fprintf(w, "export LABEL_ID=%s\n", shellQuote(out.ID))
+	for _, key := range slices.Sorted(maps.Keys(out.Tags)) {
+		fmt.Fprintf(w, "export LABEL_TAG_%s=%s\n", envName(key), shellQuote(out.Tags[key]))
+	}
+}
+
+// envName converts a tag key to an environment variable name. Words start
+// where a lowercase letter or digit is followed by an uppercase letter.
+// e.g. "CostCenter" → "COST_CENTER", "Name" → "NAME", "NAMESPACE" → "NAMESPACE"
+func envName(key string) string {
+	var b strings.Builder
+	var prev rune
+	for _, r := range key {
+		switch {
+		case unicode.IsUpper(r) && (unicode.IsLower(prev) || unicode.IsDigit(prev)):
+			b.WriteByte('_')
+			b.WriteRune(r)
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			b.WriteRune(unicode.ToUpper(r))
+		default:
+			b.WriteByte('_')
+		}
+		prev = r
+	}
+	return b.String()
+}
+
+// shellQuote wraps s in single quotes for POSIX shells.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,250 @@
+package cli
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/cloudfluent/terraform-provider-label/internal/provider"
+)
+
+func init() {
+	register("report-state", "Write a naming inventory of a state file", runReportState)
+}
+
+// Output formats of report-state.
+const (
+	formatCSV      = "csv"
+	formatMarkdown = "markdown"
+)
+
+// Inventory statuses.
+const (
+	statusCompliant    = "compliant"
+	statusLegacy       = "legacy"
+	statusNonCompliant = "non-compliant"
+	statusUnnamed      = "unnamed"
+	statusUntracked    = "untracked" // type not in the target catalog
+)
+
+// stateFile is the part of a version 4 state file the inventory reads.
+type stateFile struct {
+	Version   int             `json:"version"`
+	Resources []stateResource `json:"resources"`
+}
+
+type stateResource struct {
+	Module    string          `json:"module,omitempty"`
+	Mode      string          `json:"mode"`
+	Type      string          `json:"type"`
+	Name      string          `json:"name"`
+	Instances []stateInstance `json:"instances"`
+}
+
+type stateInstance struct {
+	IndexKey   any            `json:"index_key,omitempty"`
+	Attributes map[string]any `json:"attributes"`
+}
+
+// address returns the resource address of instance i of r,
+// e.g. module.net.aws_subnet.private["a"].
+func (r stateResource) address(i stateInstance) string {
+	var b strings.Builder
+	if r.Module != "" {
+		b.WriteString(r.Module + ".")
+	}
+	b.WriteString(r.Type + "." + r.Name)
+	switch key := i.IndexKey.(type) {
+	case string:
+		fmt.Fprintf(&b, "[%q]", key)
+	case float64:
+		fmt.Fprintf(&b, "[%d]", int(key))
+	}
+	return b.String()
+}
+
+// inventoryRow is one resource instance in the inventory.
+type inventoryRow struct {
+	Address      string            `json:"address"`
+	Type         string            `json:"type"`
+	ResourceType string            `json:"resource_type,omitempty"`
+	Name         string            `json:"name,omitempty"`
+	Status       string            `json:"status"`
+	Components   map[string]string `json:"components,omitempty"`
+	Violations   []string          `json:"violations,omitempty"`
+}
+
+func runReportState(env *Env) int {
+	fs := env.flagSet("report-state")
+	fs.Usage = func() {
+		fmt.Fprintln(env.Stderr, "Usage: terraform-provider-label report-state [flags] [terraform.tfstate | -]")
+		fmt.Fprintln(env.Stderr)
+		fs.PrintDefaults()
+	}
+	conventionFile := fs.String("convention-file", "", "convention file (default $LABEL_CONVENTION_FILE)")
+	format := fs.String("format", formatCSV, "output format: csv, markdown or json")
+	all := fs.Bool("all", false, "include resource types that are not in the target catalog")
+	if code, ok := env.parse(fs); !ok {
+		return code
+	}
+
+	if fs.NArg() > 1 {
+		fs.Usage()
+		return ExitUsage
+	}
+	input := "terraform.tfstate"
+	if fs.NArg() == 1 {
+		input = fs.Arg(0)
+	}
+	if !slices.Contains([]string{formatCSV, formatMarkdown, formatJSON}, *format) {
+		return env.errorf(ExitUsage, "unknown format %q", *format)
+	}
+
+	conv, err := env.loadConvention(*conventionFile)
+	if err != nil {
+		return env.errorf(ExitUsage, "%s", err)
+	}
+	if err := complete(conv.cfg); err != nil {
+		return env.errorf(ExitUsage, "%s", err)
+	}
+
+	state, err := env.readState(input)
+	if err != nil {
+		return env.errorf(ExitUsage, "%s", err)
+	}
+	rows := conv.inventory(state, *all)
+
+	switch *format {
+	case formatJSON:
+		enc := json.NewEncoder(env.Stdout)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(rows)
+	case formatMarkdown:
+		writeMarkdownInventory(env.Stdout, rows)
+	default:
+		err = writeCSVInventory(env.Stdout, rows)
+	}
+	if err != nil {
+		return env.errorf(ExitFail, "%s", err)
+	}
+	return ExitOK
+}
+
+// readState decodes a version 4 state file from name, or stdin for "-".
+func (env *Env) readState(name string) (*stateFile, error) {
+	f, err := env.open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var state stateFile
+	if err := json.NewDecoder(f).Decode(&state); err != nil {
+		return nil, fmt.Errorf("reading state %s: %w", name, err)
+	}
+	if state.Version != 4 {
+		return nil, fmt.Errorf("reading state %s: unsupported state version %d, want 4", name, state.Version)
+	}
+	return &state, nil
+}
+
+// inventory assesses every managed resource instance in state, sorted by
+// address. Types outside the target catalog are only listed with all.
+func (c *convention) inventory(state *stateFile, all bool) []inventoryRow {
+	rows := []inventoryRow{}
+	for _, r := range state.Resources {
+		if r.Mode != "managed" {
+			continue
+		}
+		target, tracked := c.targets[r.Type]
+		if !tracked && !all {
+			continue
+		}
+
+		for _, inst := range r.Instances {
+			row := inventoryRow{Address: r.address(inst), Type: r.Type}
+			if !tracked {
+				row.Status = statusUntracked
+				row.Name, _ = provider.Target{NameAttribute: "name"}.Name(inst.Attributes)
+				rows = append(rows, row)
+				continue
+			}
+
+			a := c.assess(row.Address, r.Type, target, inst.Attributes, nil)
+			row.ResourceType = target.Abbreviation
+			row.Name = a.Name
+			row.Components = a.Match.Components
+			for _, v := range a.Violations {
+				row.Violations = append(row.Violations, v.Message)
+			}
+			switch {
+			case !a.Known:
+				row.Status = statusUnnamed
+			case a.Legacy:
+				row.Status = statusLegacy
+			case !a.Compliant:
+				row.Status = statusNonCompliant
+			default:
+				row.Status = statusCompliant
+			}
+			rows = append(rows, row)
+		}
+	}
+
+	slices.SortFunc(rows, func(a, b inventoryRow) int { return strings.Compare(a.Address, b.Address) })
+	return rows
+}
+
+// formatComponents renders components as name=value pairs in name order.
+func formatComponents(components map[string]string) string {
+	var pairs []string
+	for _, name := range slices.Sorted(maps.Keys(components)) {
+		pairs = append(pairs, name+"="+components[name])
+	}
+	return strings.Join(pairs, " ")
+}
+
+func writeCSVInventory(w io.Writer, rows []inventoryRow) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"address", "type", "resource_type", "name", "status", "components", "violations"})
+	for _, row := range rows {
+		cw.Write([]string{
+			row.Address,
+			row.Type,
+			row.ResourceType,
+			row.Name,
+			row.Status,
+			formatComponents(row.Components),
+			strings.Join(row.Violations, "; "),
+		})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func writeMarkdownInventory(w io.Writer, rows []inventoryRow) {
+	counts := map[string]int{}
+	for _, row := range rows {
+		counts[row.Status]++
+	}
+	fmt.Fprintf(w, "%d resource(s): %d compliant, %d legacy, %d non-compliant, %d unnamed, %d untracked\n\n",
+		len(rows), counts[statusCompliant], counts[statusLegacy], counts[statusNonCompliant], counts[statusUnnamed], counts[statusUntracked])
+
+	cell := func(s string) string {
+		return strings.NewReplacer("|", `\|`, "\n", " ").Replace(s)
+	}
+	fmt.Fprintln(w, "| Address | Type | Name | Status | Components | Violations |")
+	fmt.Fprintln(w, "|---------|------|------|--------|------------|------------|")
+	for _, row := range rows {
+		name := ""
+		if row.Name != "" {
+			name = "`" + cell(row.Name) + "`"
+		}
+		fmt.Fprintf(w, "| `%s` | %s | %s | %s | %s | %s |\n",
+			cell(row.Address), row.Type, name, row.Status, cell(formatComponents(row.Components)), cell(strings.Join(row.Violations, "; ")))
+	}
+}
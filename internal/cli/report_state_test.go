@@ -0,0 +1,134 @@
+package cli
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+const testState = `{
+  "version": 4,
+  "terraform_version": "1.9.0",
+  "resources": [
+    {
+      "mode": "managed",
+      "type": "aws_security_group",
+      "name": "web",
+      "instances": [{"attributes": {"name": "web-sg", "tags_all": {"Tenant": "dpl", "Environment": "ane2", "Stage": "dev"}}}]
+    },
+    {
+      "module": "module.emr",
+      "mode": "managed",
+      "type": "aws_security_group",
+      "name": "this",
+      "instances": [
+        {"index_key": 0, "attributes": {"name": "dpl-ane2-sg-dev-emr-sales-api", "tags_all": {"Tenant": "dpl", "Environment": "ane2", "Stage": "dev"}}}
+      ]
+    },
+    {
+      "mode": "managed",
+      "type": "aws_db_instance",
+      "name": "orders",
+      "instances": [{"attributes": {"identifier": "ordersprod", "tags": {"Tenant": "dpl", "Environment": "ane2", "Stage": "dev"}}}]
+    },
+    {
+      "mode": "managed",
+      "type": "aws_vpc",
+      "name": "main",
+      "instances": [{"index_key": "a", "attributes": {"tags": {}}}]
+    },
+    {
+      "mode": "managed",
+      "type": "random_pet",
+      "name": "x",
+      "instances": [{"attributes": {"id": "fluffy-cat"}}]
+    },
+    {
+      "mode": "data",
+      "type": "label",
+      "name": "sg",
+      "instances": [{"attributes": {"id": "dpl-ane2-sg-dev-sales-api"}}]
+    }
+  ]
+}`
+
+func TestReportState(t *testing.T) {
+	convention := writeFile(t, "convention.json", `{"legacy_names": {"dpl-ane2-db-dev-orders-sales-api": "ordersprod"}}`)
+	vars := map[string]string{"LABEL_CONVENTION_FILE": convention}
+	for k, v := range testVars {
+		vars[k] = v
+	}
+
+	code, stdout, stderr := runStdin(t, vars, testState, "report-state", "--format", "json", "-")
+	if code != ExitOK {
+		t.Fatalf("exit code = %d (stderr %q)", code, stderr)
+	}
+
+	var rows []inventoryRow
+	if err := json.Unmarshal([]byte(stdout), &rows); err != nil {
+		t.Fatalf("invalid JSON %q: %v", stdout, err)
+	}
+
+	var got []string
+	for _, row := range rows {
+		got = append(got, row.Address+" "+row.Status)
+	}
+	want := []string{
+		"aws_db_instance.orders legacy",
+		"aws_security_group.web non-compliant",
+		`aws_vpc.main["a"] unnamed`,
+		"module.emr.aws_security_group.this[0] compliant",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("rows:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	emr := rows[3]
+	if emr.Components["qualifier"] != "emr" || emr.Components["workspace"] != "sales-api" || emr.ResourceType != "sg" {
+		t.Errorf("unexpected compliant row: %+v", emr)
+	}
+	if vpc := rows[2]; len(vpc.Violations) != 3 {
+		t.Errorf("vpc violations = %v, want 3 missing tags", vpc.Violations)
+	}
+}
+
+func TestReportState_Formats(t *testing.T) {
+	code, stdout, stderr := runStdin(t, testVars, testState, "report-state", "--all", "-")
+	if code != ExitOK {
+		t.Fatalf("exit code = %d (stderr %q)", code, stderr)
+	}
+	records, err := csv.NewReader(strings.NewReader(stdout)).ReadAll()
+	if err != nil {
+		t.Fatalf("invalid CSV %q: %v", stdout, err)
+	}
+	if len(records) != 6 || records[0][0] != "address" {
+		t.Fatalf("CSV has %d records, want header and 5 rows:\n%s", len(records), stdout)
+	}
+	if row := records[5]; row[0] != "random_pet.x" || row[4] != "untracked" {
+		t.Errorf("last row = %v, want untracked random_pet.x", row)
+	}
+	if row := records[4]; row[5] != "environment=ane2 qualifier=emr resource_type=sg stage=dev tenant=dpl workspace=sales-api" {
+		t.Errorf("components = %q", row[5])
+	}
+
+	code, stdout, _ = runStdin(t, testVars, testState, "report-state", "--format", "markdown", "-")
+	if code != ExitOK {
+		t.Fatalf("exit code = %d", code)
+	}
+	for _, want := range []string{
+		"4 resource(s): 1 compliant, 0 legacy, 2 non-compliant, 1 unnamed, 0 untracked",
+		"| `aws_security_group.web` | aws_security_group | `web-sg` | non-compliant |",
+	} {
+		if !strings.Contains(stdout, want) {
+			t.Errorf("markdown output missing %q:\n%s", want, stdout)
+		}
+	}
+}
+
+func TestReportState_Version(t *testing.T) {
+	code, _, stderr := runStdin(t, testVars, `{"version": 3, "modules": []}`, "report-state", "-")
+	if code != ExitUsage || !strings.Contains(stderr, "unsupported state version 3") {
+		t.Errorf("exit code = %d, stderr %q", code, stderr)
+	}
+}
@@ -0,0 +1,142 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+
+	"github.com/cloudfluent/terraform-provider-label/internal/provider"
+)
+
+func init() {
+	register("verify", "Check the tests of a convention file", runVerify)
+}
+
+// Test statuses.
+const (
+	testPass  = "pass"
+	testFail  = "fail"
+	testError = "error"
+)
+
+// testResult is the outcome of one convention test.
+type testResult struct {
+	Name   string     `json:"name"`
+	Status string     `json:"status"`
+	Diffs  []testDiff `json:"diffs,omitempty"`
+	Error  string     `json:"error,omitempty"`
+}
+
+// testDiff is a value the test expects but the convention does not
+// generate. Got is null for tags the convention does not generate.
+type testDiff struct {
+	Field string  `json:"field"`
+	Want  string  `json:"want"`
+	Got   *string `json:"got"`
+}
+
+func runVerify(env *Env) int {
+	fs := env.flagSet("verify")
+	fs.Usage = func() {
+		fmt.Fprintln(env.Stderr, "Usage: terraform-provider-label verify [flags]")
+		fmt.Fprintln(env.Stderr)
+		fmt.Fprintln(env.Stderr, "Generates the label of every test in the convention file and exits with 1")
+		fmt.Fprintln(env.Stderr, "when an ID or tag differs from the expected one.")
+		fmt.Fprintln(env.Stderr)
+		fs.PrintDefaults()
+	}
+	conventionFile := fs.String("convention-file", "", "convention file (default $"+provider.ConventionFileEnvVar+")")
+	format := fs.String("format", formatHuman, "output format: human or json")
+	verbose := fs.Bool("v", false, "list passing tests too")
+	if code, ok := env.parse(fs); !ok {
+		return code
+	}
+	if !slices.Contains([]string{formatHuman, formatJSON}, *format) {
+		return env.errorf(ExitUsage, "unknown format %q", *format)
+	}
+
+	conv, err := env.loadConvention(*conventionFile)
+	if err != nil {
+		return env.errorf(ExitUsage, "%s", err)
+	}
+	if len(conv.tests) == 0 {
+		return env.errorf(ExitUsage, "the convention file declares no tests")
+	}
+
+	results := conv.verify()
+	switch *format {
+	case formatJSON:
+		enc := json.NewEncoder(env.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(results); err != nil {
+			return env.errorf(ExitFail, "%s", err)
+		}
+	default:
+		writeTestResults(env.Stdout, results, *verbose)
+	}
+
+	if slices.ContainsFunc(results, func(r testResult) bool { return r.Status != testPass }) {
+		return ExitFail
+	}
+	return ExitOK
+}
+
+// verify runs the tests of the convention, in file order.
+func (c *convention) verify() []testResult {
+	results := make([]testResult, len(c.tests))
+	for i, test := range c.tests {
+		results[i] = testResult{Name: test.Title(), Status: testPass}
+		diffs, err := test.Run(c.cfg)
+		if err != nil {
+			results[i].Status, results[i].Error = testError, err.Error()
+			continue
+		}
+		for _, d := range diffs {
+			diff := testDiff{Field: d.Field, Want: d.Want}
+			if !d.Missing {
+				diff.Got = &d.Got
+			}
+			results[i].Diffs = append(results[i].Diffs, diff)
+			results[i].Status = testFail
+		}
+	}
+	return results
+}
+
+// writeTestResults prints a diff for every failing test, e.g.
+//
+//	FAIL emr security group
+//	  - id: "dpl-ane2-sg-dev-emr-sales-api"
+//	  + id: "dpl-ane2-sg-prd-emr-sales-api"
+func writeTestResults(w io.Writer, results []testResult, verbose bool) {
+	failed := 0
+	for _, r := range results {
+		switch r.Status {
+		case testPass:
+			if verbose {
+				fmt.Fprintf(w, "ok   %s\n", r.Name)
+			}
+			continue
+		case testError:
+			fmt.Fprintf(w, "FAIL %s: %s\n", r.Name, r.Error)
+		default:
+			fmt.Fprintf(w, "FAIL %s\n", r.Name)
+			for _, d := range r.Diffs {
+				got := "(not generated)"
+				if d.Got != nil {
+					got = fmt.Sprintf("%q", *d.Got)
+				}
+				fmt.Fprintf(w, "  - %s: %q\n", d.Field, d.Want)
+				fmt.Fprintf(w, "  + %s: %s\n", d.Field, got)
+			}
+		}
+		failed++
+	}
+	if failed > 0 {
+		fmt.Fprintln(w)
+		fmt.Fprintf(w, "%d of %d test(s) failed\n", failed, len(results))
+		return
+	}
+	fmt.Fprintf(w, "%d test(s) passed\n", len(results))
+}
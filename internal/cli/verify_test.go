@@ -0,0 +1,91 @@
+package cli
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+const testConvention = `{
+	"tenant": "dpl",
+	"environment": "ane2",
+	"tests": [
+		{
+			"name": "emr security group",
+			"resource_type": "sg",
+			"qualifier": "emr",
+			"components": {"stage": "dev", "workspace": "sales-api"},
+			"id": "dpl-ane2-sg-dev-emr-sales-api"
+		},
+		{
+			"resource_type": "role",
+			"components": {"stage": "prd"},
+			"id": "dpl-ane2-role-dev",
+			"tags": {"Stage": "dev", "Owner": "data"}
+		},
+		{
+			"name": "no stage",
+			"resource_type": "kms",
+			"components": {"stage": ""},
+			"id": "dpl-ane2-kms"
+		}
+	]
+}`
+
+func TestVerify(t *testing.T) {
+	convention := writeFile(t, "convention.json", testConvention)
+
+	code, stdout, stderr := run(t, nil, "verify", "--convention-file", convention)
+	if code != ExitFail {
+		t.Fatalf("exit code = %d, want %d (stderr %q)", code, ExitFail, stderr)
+	}
+	want := `FAIL dpl-ane2-role-dev
+  - id: "dpl-ane2-role-dev"
+  + id: "dpl-ane2-role-prd"
+  - tags.Owner: "data"
+  + tags.Owner: (not generated)
+  - tags.Stage: "dev"
+  + tags.Stage: "prd"
+FAIL no stage: missing required components: stage
+
+2 of 3 test(s) failed
+`
+	if stdout != want {
+		t.Errorf("stdout:\n%s\nwant:\n%s", stdout, want)
+	}
+
+	code, stdout, _ = run(t, nil, "verify", "--convention-file", convention, "--format", "json")
+	if code != ExitFail {
+		t.Fatalf("exit code = %d, want %d", code, ExitFail)
+	}
+	var results []testResult
+	if err := json.Unmarshal([]byte(stdout), &results); err != nil {
+		t.Fatalf("invalid JSON %q: %v", stdout, err)
+	}
+	if len(results) != 3 || results[0].Status != testPass || results[1].Status != testFail || results[2].Status != testError {
+		t.Fatalf("unexpected results: %+v", results)
+	}
+	if d := results[1].Diffs[1]; d.Field != "tags.Owner" || d.Got != nil {
+		t.Errorf("missing tag diff = %+v, want null got", d)
+	}
+}
+
+func TestVerify_Pass(t *testing.T) {
+	convention := writeFile(t, "convention.json", `{"tests": [{"resource_type": "sg", "id": "dpl-ane2-sg-dev-sales-api"}]}`)
+	code, stdout, stderr := run(t, testVars, "verify", "-v", "--convention-file", convention)
+	if code != ExitOK {
+		t.Fatalf("exit code = %d, want 0 (stdout %q, stderr %q)", code, stdout, stderr)
+	}
+	if want := "ok   dpl-ane2-sg-dev-sales-api\n1 test(s) passed\n"; stdout != want {
+		t.Errorf("stdout = %q, want %q", stdout, want)
+	}
+}
+
+func TestVerify_NoTests(t *testing.T) {
+	convention := writeFile(t, "convention.json", `{"tenant": "dpl"}`)
+	if code, _, _ := run(t, testVars, "verify", "--convention-file", convention); code != ExitUsage {
+		t.Errorf("no tests: exit code = %d, want %d", code, ExitUsage)
+	}
+	if code, _, _ := run(t, testVars, "verify"); code != ExitUsage {
+		t.Errorf("no convention file: exit code = %d, want %d", code, ExitUsage)
+	}
+}
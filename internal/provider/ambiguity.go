@@ -0,0 +1,78 @@
+package provider
+
+import "strings"
+
+// Ambiguity describes a component whose value makes an ID ambiguous.
+type Ambiguity struct {
+	Component string
+	Value     string
+}
+
+// AmbiguousComponents returns the components, in label order, whose rendered
+// value contains the delimiter. Such IDs cannot be split back into their
+// components: qualifier=emr, workspace=sales-api and qualifier=emr-sales,
+// workspace=api both yield ...-emr-sales-api.
+//
+// With cfg.EscapeDelimiter the delimiter is escaped, so only values that
+// start with the delimiter remain ambiguous.
+func AmbiguousComponents(cfg *LabelConfig, resourceType string, qualifier string, instanceKey string, delimiter string) []Ambiguity {
+	if delimiter == "" {
+		delimiter = cfg.Delimiter
+	}
+
+	in := labelInputs{resourceType: resourceType, qualifier: qualifier, instanceKey: instanceKey}
+
+	var out []Ambiguity
+	for _, name := range cfg.labelOrder() {
+		segs := cfg.segments(name, in)
+		if len(segs) == 0 {
+			continue
+		}
+		value := strings.Join(segs, delimiter)
+		ambiguous := strings.Contains(value, delimiter)
+		if cfg.EscapeDelimiter {
+			ambiguous = strings.HasPrefix(value, delimiter)
+		}
+		if ambiguous {
+			out = append(out, Ambiguity{Component: name, Value: value})
+		}
+	}
+	return out
+}
+
+// EscapeSegment doubles every delimiter in value so the joined ID stays
+// reversible with ParseID.
+func EscapeSegment(value string, delimiter string) string {
+	return strings.ReplaceAll(value, delimiter, delimiter+delimiter)
+}
+
+// ParseID splits an ID generated with EscapeDelimiter back into its
+// component values. A run of n delimiters holds n/2 escaped delimiters,
+// followed by a separator when n is odd.
+// e.g. "dpl-ane2-sg-dev-sales--api" → [dpl ane2 sg dev sales-api]
+func ParseID(id string, delimiter string) []string {
+	if id == "" || delimiter == "" {
+		return nil
+	}
+
+	var parts []string
+	var cur strings.Builder
+	for i := 0; i < len(id); {
+		if !strings.HasPrefix(id[i:], delimiter) {
+			cur.WriteByte(id[i])
+			i++
+			continue
+		}
+		n := 0
+		for strings.HasPrefix(id[i:], delimiter) {
+			n++
+			i += len(delimiter)
+		}
+		cur.WriteString(strings.Repeat(delimiter, n/2))
+		if n%2 == 1 {
+			parts = append(parts, cur.String())
+			cur.Reset()
+		}
+	}
+	return append(parts, cur.String())
+}
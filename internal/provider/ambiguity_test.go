@@ -0,0 +1,152 @@
+package provider
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestAmbiguousComponents(t *testing.T) {
+	tests := []struct {
+		name      string
+		workspace string
+		qualifier string
+		delimiter string
+		escape    bool
+		want      []Ambiguity
+	}{
+		{
+			name:      "single segments",
+			workspace: "api",
+			qualifier: "emr",
+		},
+		{
+			name:      "qualifier contains delimiter",
+			workspace: "api",
+			qualifier: "emr-sales",
+			want:      []Ambiguity{{Component: "qualifier", Value: "emr-sales"}},
+		},
+		{
+			name:      "multi-segment workspace",
+			workspace: "sales-api",
+			qualifier: "emr",
+			want:      []Ambiguity{{Component: "workspace", Value: "sales-api"}},
+		},
+		{
+			name:      "other delimiter",
+			workspace: "sales-api",
+			qualifier: "emr-sales",
+			delimiter: "_",
+			want:      []Ambiguity{{Component: "workspace", Value: "sales_api"}},
+		},
+		{
+			name:      "escaped",
+			workspace: "sales-api",
+			qualifier: "emr-sales",
+			escape:    true,
+		},
+		{
+			name:      "escaped leading delimiter",
+			workspace: "api",
+			qualifier: "-emr",
+			escape:    true,
+			want:      []Ambiguity{{Component: "qualifier", Value: "-emr"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &LabelConfig{
+				Tenant:          "dpl",
+				Environment:     "ane2",
+				Stage:           "dev",
+				Workspace:       tt.workspace,
+				Delimiter:       "-",
+				EscapeDelimiter: tt.escape,
+			}
+			got := AmbiguousComponents(cfg, "sg", tt.qualifier, "", tt.delimiter)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("AmbiguousComponents() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerateID_EscapeDelimiter(t *testing.T) {
+	tests := []struct {
+		workspace string
+		qualifier string
+		delimiter string
+		want      string
+	}{
+		{"sales-api", "emr", "", "dpl-ane2-sg-dev-emr-sales--api"},
+		{"api", "emr-sales", "", "dpl-ane2-sg-dev-emr--sales-api"},
+		{"sales-api", "emr", "_", "dpl_ane2_sg_dev_emr_sales__api"},
+		{"api", "", "", "dpl-ane2-sg-dev-api"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			cfg := &LabelConfig{
+				Tenant:          "dpl",
+				Environment:     "ane2",
+				Stage:           "dev",
+				Workspace:       tt.workspace,
+				Delimiter:       "-",
+				EscapeDelimiter: true,
+			}
+			got := GenerateID(cfg, "sg", tt.qualifier, "", tt.delimiter)
+			if got != tt.want {
+				t.Errorf("GenerateID() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseID(t *testing.T) {
+	tests := []struct {
+		id        string
+		delimiter string
+		want      []string
+	}{
+		{"dpl-ane2-sg-dev-emr-sales--api", "-", []string{"dpl", "ane2", "sg", "dev", "emr", "sales-api"}},
+		{"dpl-ane2-sg-dev-emr--sales-api", "-", []string{"dpl", "ane2", "sg", "dev", "emr-sales", "api"}},
+		{"a---b", "-", []string{"a-", "b"}},
+		{"a__b_c", "_", []string{"a_b", "c"}},
+		{"a::b::::c", "::", []string{"a", "b::c"}},
+		{"single", "-", []string{"single"}},
+		{"", "-", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.id, func(t *testing.T) {
+			got := ParseID(tt.id, tt.delimiter)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("ParseID(%q) = %q, want %q", tt.id, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseID_RoundTrip(t *testing.T) {
+	values := [][]string{
+		{"dpl", "ane2", "sg", "dev", "emr-sales", "api"},
+		{"dpl", "ane2", "sg", "dev", "emr", "sales-api"},
+		{"dpl", "x--y", "z-"},
+	}
+	for _, parts := range values {
+		escaped := make([]string, len(parts))
+		for i, p := range parts {
+			escaped[i] = EscapeSegment(p, "-")
+		}
+		id := ""
+		for i, p := range escaped {
+			if i > 0 {
+				id += "-"
+			}
+			id += p
+		}
+		if got := ParseID(id, "-"); !slices.Equal(got, parts) {
+			t.Errorf("ParseID(%q) = %q, want %q", id, got, parts)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package provider
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+)
+
+// ConventionFileEnvVar names the convention file when convention_file is
+// not set.
+const ConventionFileEnvVar = "LABEL_CONVENTION_FILE"
+
+// ConventionFile is a JSON document that holds a shared naming convention,
+// so that Terraform and other tooling generate the same names. Its keys are
+// the provider attributes, e.g.
+//
+//	{
+//	  "tenant": "dpl",
+//	  "label_order": ["tenant", "environment", "resource_type", "stage"],
+//	  "legacy_names": {"dpl-ane2-db-dev": "ordersprod"}
+//	}
+type ConventionFile struct {
+	Settings
+
+	// Targets extend or override DefaultTargets for the command line. The
+	// provider ignores them.
+	Targets map[string]Target `json:"targets,omitempty"`
+
+	// Tests are examples the convention must keep generating, checked by
+	// the verify command. The provider ignores them.
+	Tests []ConventionTest `json:"tests,omitempty"`
+
+	// Path is the file the convention was loaded from.
+	Path string `json:"-"`
+}
+
+// LoadConventionFile reads and parses a convention file. Unknown keys are
+// rejected so that typos do not silently change names.
+func LoadConventionFile(path string) (*ConventionFile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	var f ConventionFile
+	if err := dec.Decode(&f); err != nil {
+		return nil, fmt.Errorf("parsing convention file %s: %w", path, err)
+	}
+	f.Path = path
+	return &f, nil
+}
+
+// Source describes the file in component sources.
+func (f *ConventionFile) Source() string {
+	return "convention file " + f.Path
+}
+
+// Catalog returns DefaultTargets extended with the targets of the file. A
+// nil file yields DefaultTargets.
+func (f *ConventionFile) Catalog() map[string]Target {
+	catalog := maps.Clone(DefaultTargets)
+	if f != nil {
+		maps.Copy(catalog, f.Targets)
+	}
+	return catalog
+}
@@ -3,10 +3,14 @@ package provider
 import (
 	"context"
 	"fmt"
+	"maps"
 	"strings"
 
+	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 )
 
@@ -17,13 +21,54 @@ type LabelDataSource struct {
 }
 
 type LabelDataSourceModel struct {
-	ResourceType    types.String `tfsdk:"resource_type"`
-	Qualifier       types.String `tfsdk:"qualifier"`
-	InstanceKey     types.String `tfsdk:"instance_key"`
-	Delimiter       types.String `tfsdk:"delimiter"`
-	Id              types.String `tfsdk:"id"`
-	Tags            types.Map    `tfsdk:"tags"`
-	TagsWithoutName types.Map    `tfsdk:"tags_without_name"`
+	ResourceType     types.String `tfsdk:"resource_type"`
+	Qualifier        types.String `tfsdk:"qualifier"`
+	InstanceKey      types.String `tfsdk:"instance_key"`
+	Delimiter        types.String `tfsdk:"delimiter"`
+	Tenant           types.String `tfsdk:"tenant"`
+	Environment      types.String `tfsdk:"environment"`
+	Stage            types.String `tfsdk:"stage"`
+	Workspace        types.String `tfsdk:"workspace"`
+	Namespace        types.String `tfsdk:"namespace"`
+	UniqueSuffix     types.Bool   `tfsdk:"unique_suffix"`
+	SuffixSeed       types.List   `tfsdk:"suffix_seed"`
+	SuffixLength     types.Int64  `tfsdk:"suffix_length"`
+	SuffixAlphabet   types.String `tfsdk:"suffix_alphabet"`
+	LegacyKey        types.String `tfsdk:"legacy_key"`
+	IDLengthLimit    types.Int64  `tfsdk:"id_length_limit"`
+	ContextJSON      types.String `tfsdk:"context_json"`
+	Id               types.String `tfsdk:"id"`
+	Suffix           types.String `tfsdk:"suffix"`
+	IsLegacy         types.Bool   `tfsdk:"is_legacy"`
+	Attributes       types.List   `tfsdk:"attributes"`
+	Tags             types.Map    `tfsdk:"tags"`
+	TagsWithoutName  types.Map    `tfsdk:"tags_without_name"`
+	NullLabelContext types.Object `tfsdk:"null_label_context"`
+	Sources          types.Map    `tfsdk:"sources"`
+}
+
+// nullLabelContextType is the object type of null_label_context.
+var nullLabelContextType = map[string]attr.Type{
+	"enabled":             types.BoolType,
+	"namespace":           types.StringType,
+	"tenant":              types.StringType,
+	"environment":         types.StringType,
+	"stage":               types.StringType,
+	"name":                types.StringType,
+	"delimiter":           types.StringType,
+	"attributes":          types.ListType{ElemType: types.StringType},
+	"tags":                types.MapType{ElemType: types.StringType},
+	"additional_tag_map":  types.MapType{ElemType: types.StringType},
+	"label_order":         types.ListType{ElemType: types.StringType},
+	"regex_replace_chars": types.StringType,
+	"id_length_limit":     types.Int64Type,
+	"label_key_case":      types.StringType,
+	"label_value_case":    types.StringType,
+	"labels_as_tags":      types.ListType{ElemType: types.StringType},
+	"descriptor_formats": types.MapType{ElemType: types.ObjectType{AttrTypes: map[string]attr.Type{
+		"format": types.StringType,
+		"labels": types.ListType{ElemType: types.StringType},
+	}}},
 }
 
 func NewLabelDataSource() datasource.DataSource {
@@ -54,9 +99,72 @@ func (d *LabelDataSource) Schema(_ context.Context, _ datasource.SchemaRequest,
 				Optional:    true,
 				Description: "Override the provider-level delimiter for this resource",
 			},
+			"tenant": schema.StringAttribute{
+				Optional:    true,
+				Description: "Override the provider-level tenant for this resource",
+			},
+			"environment": schema.StringAttribute{
+				Optional:    true,
+				Description: "Override the provider-level environment for this resource",
+			},
+			"stage": schema.StringAttribute{
+				Optional:    true,
+				Description: "Override the provider-level stage for this resource (e.g. a cross-stage shared key)",
+			},
+			"workspace": schema.StringAttribute{
+				Optional:    true,
+				Description: "Override the provider-level workspace for this resource. Set to \"\" to omit the workspace.",
+			},
+			"namespace": schema.StringAttribute{
+				Optional:    true,
+				Description: "Override the provider-level namespace for this resource",
+			},
+			"unique_suffix": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Append a deterministic hash suffix for globally unique names (e.g. S3 buckets)",
+			},
+			"suffix_seed": schema.ListAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				Description: "Values the unique suffix is derived from (e.g. account ID, tenant, workspace). Defaults to the ID. The provider suffix_salt is always added.",
+			},
+			"suffix_length": schema.Int64Attribute{
+				Optional:    true,
+				Description: fmt.Sprintf("Length of the unique suffix (default: %d, max: %d)", DefaultSuffixLength, MaxSuffixLength),
+			},
+			"suffix_alphabet": schema.StringAttribute{
+				Optional:    true,
+				Description: "Characters the unique suffix is drawn from (default: a-z0-9)",
+			},
+			"legacy_key": schema.StringAttribute{
+				Optional:    true,
+				Description: "Stable key to look up in the provider legacy_names map, checked before the computed ID",
+			},
+			"id_length_limit": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Override the provider-level id_length_limit for this resource. Requires compat = \"null-label\".",
+			},
+			"context_json": schema.StringAttribute{
+				Optional:    true,
+				Description: "A null-label context passed through jsonencode(module.<name>.context). Its labels, name, delimiter, attributes and tags fill in what is not set on the data source; its null-label settings apply with compat = \"null-label\".",
+			},
 			"id": schema.StringAttribute{
 				Computed:    true,
-				Description: "Generated resource identifier",
+				Description: "Generated resource identifier, or the legacy name when one is registered",
+			},
+			"is_legacy": schema.BoolAttribute{
+				Computed:    true,
+				Description: "Whether id is a legacy name from the provider legacy_names map",
+			},
+			"suffix": schema.StringAttribute{
+				Computed:    true,
+				Description: "Unique suffix appended to the ID, or null when unique_suffix is not set",
+			},
+			"attributes": schema.ListAttribute{
+				Optional:    true,
+				Computed:    true,
+				ElementType: types.StringType,
+				Description: "Segments that make up the Attributes tag, in order. With compat = \"null-label\" it may be set to the null-label attributes, which precede instance_key.",
 			},
 			"tags": schema.MapAttribute{
 				Computed:    true,
@@ -68,6 +176,16 @@ func (d *LabelDataSource) Schema(_ context.Context, _ datasource.SchemaRequest,
 				ElementType: types.StringType,
 				Description: "Generated resource tags without Name key",
 			},
+			"null_label_context": schema.ObjectAttribute{
+				Computed:       true,
+				AttributeTypes: nullLabelContextType,
+				Description:    "This label as a null-label context, to pass as context to null-label modules",
+			},
+			"sources": schema.MapAttribute{
+				Computed:    true,
+				ElementType: types.StringType,
+				Description: "Where the value of each provider-level component came from, e.g. config, env LABEL_STAGE, detector tfc (TFC_WORKSPACE_NAME), context_json or data source",
+			},
 		},
 	}
 }
@@ -104,21 +222,68 @@ func (d *LabelDataSource) Read(ctx context.Context, req datasource.ReadRequest,
 		return
 	}
 
-	var missing []string
-	if d.config.Tenant == "" {
-		missing = append(missing, "tenant")
+	var nlContext *NullLabelContext
+	if !model.ContextJSON.IsNull() {
+		c, err := ParseNullLabelContext(model.ContextJSON.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("context_json"), "Invalid Null-label Context", err.Error())
+			return
+		}
+		if c.Enabled != nil && !*c.Enabled {
+			resp.Diagnostics.AddAttributeWarning(path.Root("context_json"), "Disabled Null-label Context",
+				"The context has enabled = false. The label data source has no enabled flag and generates the ID anyway.")
+		}
+		nlContext = c
+	}
+
+	// Values set on the data source win over the context, which wins over
+	// the provider.
+	contextValue := func(name string) *string {
+		if nlContext == nil {
+			return nil
+		}
+		switch name {
+		case "tenant":
+			return nlContext.Tenant
+		case "environment":
+			return nlContext.Environment
+		case "stage":
+			return nlContext.Stage
+		case "namespace":
+			return nlContext.Namespace
+		case "qualifier":
+			return nlContext.Name
+		case "delimiter":
+			return nlContext.Delimiter
+		}
+		return nil
 	}
-	if d.config.Environment == "" {
-		missing = append(missing, "environment")
+
+	overrides := map[string]string{}
+	sources := map[string]string{}
+	for name, value := range map[string]types.String{
+		"tenant":      model.Tenant,
+		"environment": model.Environment,
+		"stage":       model.Stage,
+		"workspace":   model.Workspace,
+		"namespace":   model.Namespace,
+	} {
+		if !value.IsNull() {
+			overrides[name] = value.ValueString()
+			sources[name] = "data source"
+		} else if v := contextValue(name); v != nil {
+			overrides[name] = *v
+			sources[name] = "context_json"
+		}
 	}
-	if d.config.Stage == "" {
-		missing = append(missing, "stage")
+	cfg := d.config.WithOverrides(overrides)
+	if cfg.Sources == nil {
+		cfg.Sources = map[string]string{}
 	}
-	if len(missing) > 0 {
-		resp.Diagnostics.AddError(
-			"Incomplete Provider Configuration",
-			fmt.Sprintf("Missing required provider values: %s", strings.Join(missing, ", ")),
-		)
+	maps.Copy(cfg.Sources, sources)
+
+	if missing := cfg.MissingComponents(); len(missing) > 0 {
+		resp.Diagnostics.AddError("Incomplete Provider Configuration", missingComponentsDetail(missing))
 		return
 	}
 
@@ -127,19 +292,168 @@ func (d *LabelDataSource) Read(ctx context.Context, req datasource.ReadRequest,
 	var qualifier, instanceKey, delimiter string
 	if !model.Qualifier.IsNull() {
 		qualifier = model.Qualifier.ValueString()
+	} else if v := contextValue("qualifier"); v != nil {
+		qualifier = *v
 	}
 	if !model.InstanceKey.IsNull() {
 		instanceKey = model.InstanceKey.ValueString()
 	}
 	if !model.Delimiter.IsNull() {
 		delimiter = model.Delimiter.ValueString()
+	} else if v := contextValue("delimiter"); v != nil {
+		delimiter = *v
+	}
+
+	effective := delimiter
+	if effective == "" {
+		effective = cfg.Delimiter
+	}
+
+	nullLabel := cfg.Compat == CompatNullLabel
+	if nlContext != nil && !nullLabel {
+		// Without compat the context attributes become the instance key.
+		if model.InstanceKey.IsNull() {
+			instanceKey = strings.Join(compactDistinct(nlContext.Attributes), effective)
+		}
+		if ignored := nlContext.Settings(); len(ignored) > 0 {
+			resp.Diagnostics.AddAttributeWarning(path.Root("context_json"), "Null-label Context Settings Ignored",
+				fmt.Sprintf("The context sets %s, which only apply when the provider has compat = %q.", strings.Join(ignored, ", "), CompatNullLabel))
+		}
+	}
+	if !nullLabel {
+		for _, attr := range []struct {
+			name string
+			set  bool
+		}{
+			{"attributes", !model.Attributes.IsNull() && !model.Attributes.IsUnknown()},
+			{"id_length_limit", !model.IDLengthLimit.IsNull()},
+		} {
+			if attr.set {
+				resp.Diagnostics.AddAttributeError(path.Root(attr.name), "Null-label Setting Without Compatibility Mode",
+					fmt.Sprintf("%s can only be set when the provider has compat = %q.", attr.name, CompatNullLabel))
+			}
+		}
+	} else if model.UniqueSuffix.ValueBool() {
+		resp.Diagnostics.AddAttributeError(path.Root("unique_suffix"), "Unique Suffix With Null-label Compatibility",
+			"null-label has no unique suffix. Use id_length_limit to shorten IDs instead.")
+	}
+	if resp.Diagnostics.HasError() {
+		return
 	}
 
-	id := GenerateID(d.config, resourceType, qualifier, instanceKey, delimiter)
-	tags := GenerateTags(d.config, resourceType, qualifier, instanceKey, delimiter)
+	var id string
+	var tags map[string]string
+	var attributeValues []string
+	var outputContext NullLabelContext
+	if nullLabel {
+		nl, diags := nullLabelInputs(ctx, cfg, model, nlContext, qualifier, instanceKey, delimiter)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		out := nl.Generate()
+		id, tags, attributeValues = out.ID, out.Tags, out.Attributes
+		outputContext = nl.Context(out)
+	} else {
+		id = GenerateID(cfg, resourceType, qualifier, instanceKey, delimiter)
+		attributeValues = GenerateAttributes(cfg, resourceType, qualifier, instanceKey)
+	}
+
+	model.Suffix = types.StringNull()
+	if model.UniqueSuffix.ValueBool() {
+		length := DefaultSuffixLength
+		if !model.SuffixLength.IsNull() {
+			length = int(model.SuffixLength.ValueInt64())
+		}
+		alphabet := DefaultSuffixAlphabet
+		if !model.SuffixAlphabet.IsNull() {
+			alphabet = model.SuffixAlphabet.ValueString()
+		}
+		if err := validateSuffix(length, alphabet); err != nil {
+			resp.Diagnostics.AddError("Invalid Unique Suffix", err.Error())
+			return
+		}
+
+		seed := []string{id}
+		if !model.SuffixSeed.IsNull() {
+			seed = nil
+			resp.Diagnostics.Append(model.SuffixSeed.ElementsAs(ctx, &seed, false)...)
+			if resp.Diagnostics.HasError() {
+				return
+			}
+		}
+		if cfg.SuffixSalt != "" {
+			seed = append(seed, cfg.SuffixSalt)
+		}
+
+		suffix := UniqueSuffix(seed, length, alphabet)
+		id = id + effective + suffix
+		model.Suffix = types.StringValue(suffix)
+	}
+
+	// Tags keep the convention name even when id is a legacy name.
+	conventionID := id
+	legacy, isLegacy := cfg.LegacyName(model.LegacyKey.ValueString(), id)
+	if isLegacy {
+		id = legacy
+	}
+	model.IsLegacy = types.BoolValue(isLegacy)
+
+	if !isLegacy && !nullLabel && (cfg.AmbiguityCheck == CheckWarn || cfg.AmbiguityCheck == CheckError) {
+		if ambiguous := AmbiguousComponents(cfg, resourceType, qualifier, instanceKey, delimiter); len(ambiguous) > 0 {
+			lines := make([]string, 0, len(ambiguous))
+			for _, a := range ambiguous {
+				lines = append(lines, fmt.Sprintf("  - %s = %q", a.Component, a.Value))
+			}
+			summary := "Ambiguous Label ID"
+			detail := fmt.Sprintf(
+				"ID %q cannot be split back into its components because these values contain the delimiter %q:\n%s\n\nRemove the delimiter from these values or set escape_delimiter = true in the provider block.",
+				id, effective, strings.Join(lines, "\n"),
+			)
+			if cfg.EscapeDelimiter {
+				detail = fmt.Sprintf(
+					"ID %q cannot be split back into its components because these values start with the delimiter %q:\n%s",
+					id, effective, strings.Join(lines, "\n"),
+				)
+			}
+			if cfg.AmbiguityCheck == CheckError {
+				resp.Diagnostics.AddError(summary, detail)
+				return
+			}
+			resp.Diagnostics.AddWarning(summary, detail)
+		}
+	}
+
+	if !checkDuplicate(cfg, ownerRead, id, describeLabel(model), &resp.Diagnostics) {
+		return
+	}
+
+	nameKey := "Name"
+	if nullLabel {
+		nameKey = nullLabelTagKey("name", cfg.NullLabel.LabelKeyCase)
+	} else {
+		tags = GenerateTags(cfg, resourceType, qualifier, instanceKey, delimiter)
+		tags[nameKey] = conventionID
+		// Context tags are kept unless the convention generates them.
+		if nlContext != nil {
+			for k, v := range nlContext.Tags {
+				if _, ok := tags[k]; !ok {
+					tags[k] = v
+				}
+			}
+		}
+		outputContext = ConventionContext(cfg, qualifier, instanceKey, delimiter, tags)
+	}
 
 	model.Id = types.StringValue(id)
 
+	attributes, diags := types.ListValueFrom(ctx, types.StringType, attributeValues)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	model.Attributes = attributes
+
 	tagsMap, diags := types.MapValueFrom(ctx, types.StringType, tags)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
@@ -149,7 +463,7 @@ func (d *LabelDataSource) Read(ctx context.Context, req datasource.ReadRequest,
 
 	tagsNoName := make(map[string]string, len(tags))
 	for k, v := range tags {
-		if k != "Name" {
+		if k != nameKey {
 			tagsNoName[k] = v
 		}
 	}
@@ -160,5 +474,85 @@ func (d *LabelDataSource) Read(ctx context.Context, req datasource.ReadRequest,
 	}
 	model.TagsWithoutName = tagsNoNameMap
 
+	contextObject, diags := types.ObjectValueFrom(ctx, nullLabelContextType, outputContext)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	model.NullLabelContext = contextObject
+
+	sourcesMap, diags := types.MapValueFrom(ctx, types.StringType, cfg.ComponentSources())
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	model.Sources = sourcesMap
+
 	resp.Diagnostics.Append(resp.State.Set(ctx, &model)...)
 }
+
+// nullLabelInputs returns the terraform-null-label inputs of the label:
+// qualifier is its name, and the attributes of the context and the data
+// source followed by instanceKey are its attributes. The null-label settings
+// of the context override the provider, like they do in null-label.
+func nullLabelInputs(ctx context.Context, cfg *LabelConfig, model LabelDataSourceModel, nlContext *NullLabelContext, qualifier, instanceKey, delimiter string) (NullLabel, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	var attributes []string
+	if nlContext != nil {
+		attributes = append(attributes, nlContext.Attributes...)
+	}
+	if !model.Attributes.IsNull() && !model.Attributes.IsUnknown() {
+		var own []string
+		diags.Append(model.Attributes.ElementsAs(ctx, &own, false)...)
+		if diags.HasError() {
+			return NullLabel{}, diags
+		}
+		attributes = append(attributes, own...)
+	}
+
+	nl := NullLabelFor(cfg, qualifier, append(attributes, instanceKey), delimiter)
+	if nlContext != nil {
+		nlContext.Apply(&nl)
+		nl.Tags = nlContext.Tags
+	}
+	if !model.IDLengthLimit.IsNull() {
+		nl.IDLengthLimit = int(model.IDLengthLimit.ValueInt64())
+		if err := validateIDLengthLimit(nl.IDLengthLimit); err != nil {
+			diags.AddAttributeError(path.Root("id_length_limit"), "Invalid ID Length Limit", err.Error())
+			return NullLabel{}, diags
+		}
+	}
+	return nl, diags
+}
+
+// describeLabel identifies a label data source by its inputs.
+func describeLabel(model LabelDataSourceModel) string {
+	inputs := []labelInput{
+		{"resource_type", model.ResourceType},
+		{"qualifier", model.Qualifier},
+		{"instance_key", model.InstanceKey},
+		{"tenant", model.Tenant},
+		{"environment", model.Environment},
+		{"stage", model.Stage},
+		{"workspace", model.Workspace},
+		{"namespace", model.Namespace},
+		{"delimiter", model.Delimiter},
+		{"legacy_key", model.LegacyKey},
+		{"context_json", model.ContextJSON},
+	}
+	return describeBlock(`data "label"`, inputs)
+}
+
+// missingComponentsDetail lists missing required components together with
+// where each can be set.
+func missingComponentsDetail(missing []string) string {
+	lines := make([]string, 0, len(missing))
+	for _, name := range missing {
+		attr := name
+		if IsCustomComponent(name) {
+			attr = fmt.Sprintf("components.%s", name)
+		}
+		lines = append(lines, fmt.Sprintf("  - %s (set %s in the provider block or %s)", name, attr, ComponentEnvVar(name)))
+	}
+	return fmt.Sprintf("Missing required provider values:\n%s", strings.Join(lines, "\n"))
+}
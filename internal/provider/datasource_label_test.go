@@ -1,6 +1,7 @@
 package provider
 
 import (
+	"regexp"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-framework/providerserver"
@@ -363,6 +364,36 @@ output "id" {
 	})
 }
 
+// TestLabelDataSource_Sources tests where component values came from.
+func TestLabelDataSource_Sources(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testProviderConfigWithValues + `
+data "label" "sg" {
+  resource_type = "sg"
+  stage         = "prd"
+}
+
+output "sources" {
+  value = data.label.sg.sources
+}
+`,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownOutputValue("sources", knownvalue.MapExact(map[string]knownvalue.Check{
+						"tenant":      knownvalue.StringExact("config"),
+						"environment": knownvalue.StringExact("config"),
+						"stage":       knownvalue.StringExact("data source"),
+						"workspace":   knownvalue.StringExact("config"),
+						"namespace":   knownvalue.StringExact("config"),
+					})),
+				},
+			},
+		},
+	})
+}
+
 // TestLabelDataSource_EksWorkspace tests underscore-containing workspace.
 func TestLabelDataSource_EksWorkspace(t *testing.T) {
 	resource.UnitTest(t, resource.TestCase{
@@ -490,3 +521,712 @@ output "vpc_tags" {
 		},
 	})
 }
+
+// TestLabelDataSource_RequiredComponents tests that every missing required component is reported.
+func TestLabelDataSource_RequiredComponents(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testProviderConfig + `
+provider "label" {
+  tenant              = "dpl"
+  required_components = ["tenant", "stage", "workspace"]
+}
+
+data "label" "sg" {
+  resource_type = "sg"
+}
+`,
+				ExpectError: regexp.MustCompile(`(?s)stage.*LABEL_STAGE.*workspace.*LABEL_WORKSPACE`),
+			},
+		},
+	})
+}
+
+// TestLabelDataSource_RequiredComponentsRelaxed tests that defaults can be dropped.
+func TestLabelDataSource_RequiredComponentsRelaxed(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testProviderConfig + `
+provider "label" {
+  tenant              = "dpl"
+  stage               = "dev"
+  required_components = ["tenant", "stage"]
+}
+
+data "label" "sg" {
+  resource_type = "sg"
+}
+
+output "id" {
+  value = data.label.sg.id
+}
+`,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownOutputValue("id", knownvalue.StringExact("dpl-sg-dev")),
+				},
+			},
+		},
+	})
+}
+
+// TestLabelDataSource_CustomComponents tests custom components in label_order and tags.
+func TestLabelDataSource_CustomComponents(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testProviderConfig + `
+provider "label" {
+  tenant      = "dpl"
+  environment = "ane2"
+  stage       = "dev"
+  workspace   = "sales-api"
+  components = {
+    account     = "core"
+    cost_center = "cc42"
+  }
+  label_order = ["tenant", "account", "environment", "resource_type", "stage", "qualifier", "workspace"]
+}
+
+data "label" "sg" {
+  resource_type = "sg"
+  qualifier     = "emr"
+}
+
+output "id" {
+  value = data.label.sg.id
+}
+
+output "tags" {
+  value = data.label.sg.tags
+}
+`,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownOutputValue("id", knownvalue.StringExact("dpl-core-ane2-sg-dev-emr-sales-api")),
+					statecheck.ExpectKnownOutputValue("tags", knownvalue.MapExact(map[string]knownvalue.Check{
+						"Name":        knownvalue.StringExact("dpl-core-ane2-sg-dev-emr-sales-api"),
+						"Tenant":      knownvalue.StringExact("dpl"),
+						"Environment": knownvalue.StringExact("ane2"),
+						"Stage":       knownvalue.StringExact("dev"),
+						"Account":     knownvalue.StringExact("core"),
+						"CostCenter":  knownvalue.StringExact("cc42"),
+						"Attributes":  knownvalue.StringExact("emr-sales-api"),
+					})),
+				},
+			},
+		},
+	})
+}
+
+// TestLabelDataSource_Overrides tests per-data-source overrides of provider values.
+func TestLabelDataSource_Overrides(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testProviderConfigWithValues + `
+data "label" "kms" {
+  resource_type = "kms"
+  stage         = "shared"
+  workspace     = "core"
+}
+
+data "label" "vpce" {
+  resource_type = "vpce"
+  tenant        = "com"
+  workspace     = ""
+}
+
+data "label" "default" {
+  resource_type = "sg"
+}
+
+output "kms_id" {
+  value = data.label.kms.id
+}
+
+output "vpce_id" {
+  value = data.label.vpce.id
+}
+
+output "default_id" {
+  value = data.label.default.id
+}
+
+output "kms_tags" {
+  value = data.label.kms.tags
+}
+`,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownOutputValue("kms_id", knownvalue.StringExact("dpl-ane2-kms-shared-core")),
+					statecheck.ExpectKnownOutputValue("vpce_id", knownvalue.StringExact("com-ane2-vpce-dev")),
+					statecheck.ExpectKnownOutputValue("default_id", knownvalue.StringExact("dpl-ane2-sg-dev-sales-api")),
+					statecheck.ExpectKnownOutputValue("kms_tags", knownvalue.MapExact(map[string]knownvalue.Check{
+						"Name":        knownvalue.StringExact("dpl-ane2-kms-shared-core"),
+						"Namespace":   knownvalue.StringExact("acme"),
+						"Tenant":      knownvalue.StringExact("dpl"),
+						"Environment": knownvalue.StringExact("ane2"),
+						"Stage":       knownvalue.StringExact("shared"),
+						"Attributes":  knownvalue.StringExact("core"),
+					})),
+				},
+			},
+		},
+	})
+}
+
+// TestLabelDataSource_OverrideSatisfiesRequired tests that overrides count toward required components.
+func TestLabelDataSource_OverrideSatisfiesRequired(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testProviderConfig + `
+provider "label" {
+  tenant      = "dpl"
+  environment = "ane2"
+}
+
+data "label" "sg" {
+  resource_type = "sg"
+  stage         = "prd"
+}
+
+output "id" {
+  value = data.label.sg.id
+}
+`,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownOutputValue("id", knownvalue.StringExact("dpl-ane2-sg-prd")),
+				},
+			},
+		},
+	})
+}
+
+// TestLabelDataSource_Aliases tests that aliased values are normalized in both the ID and tags.
+func TestLabelDataSource_Aliases(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testProviderConfig + `
+provider "label" {
+  tenant         = "dpl"
+  environment    = "ane2"
+  stage          = "production"
+  workspace      = "sales-api"
+  raw_value_tags = true
+  aliases = {
+    stage = {
+      production  = "prd"
+      development = "dev"
+    }
+  }
+}
+
+data "label" "sg" {
+  resource_type = "sg"
+}
+
+data "label" "sg_dev" {
+  resource_type = "sg"
+  stage         = "Development"
+}
+
+output "id" {
+  value = data.label.sg.id
+}
+
+output "dev_id" {
+  value = data.label.sg_dev.id
+}
+
+output "tags" {
+  value = data.label.sg.tags
+}
+`,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownOutputValue("id", knownvalue.StringExact("dpl-ane2-sg-prd-sales-api")),
+					statecheck.ExpectKnownOutputValue("dev_id", knownvalue.StringExact("dpl-ane2-sg-dev-sales-api")),
+					statecheck.ExpectKnownOutputValue("tags", knownvalue.MapExact(map[string]knownvalue.Check{
+						"Name":        knownvalue.StringExact("dpl-ane2-sg-prd-sales-api"),
+						"Tenant":      knownvalue.StringExact("dpl"),
+						"Environment": knownvalue.StringExact("ane2"),
+						"Stage":       knownvalue.StringExact("prd"),
+						"StageRaw":    knownvalue.StringExact("production"),
+						"Attributes":  knownvalue.StringExact("sales-api"),
+					})),
+				},
+			},
+		},
+	})
+}
+
+// TestLabelDataSource_WorkspaceDetection tests deriving workspace and stage from a CI/CD variable.
+func TestLabelDataSource_WorkspaceDetection(t *testing.T) {
+	t.Setenv("SCALR_WORKSPACE_NAME", "sales-api-prd")
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testProviderConfig + `
+provider "label" {
+  tenant              = "dpl"
+  environment         = "ane2"
+  workspace_detectors = ["tfc", "scalr"]
+  workspace_pattern   = "^(?P<workspace>.+)-(?P<stage>dev|prd)$"
+}
+
+data "label" "sg" {
+  resource_type = "sg"
+}
+
+output "id" {
+  value = data.label.sg.id
+}
+`,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownOutputValue("id", knownvalue.StringExact("dpl-ane2-sg-prd-sales-api")),
+				},
+			},
+		},
+	})
+}
+
+// TestLabelDataSource_WorkspaceDelimiter tests custom workspace splitting and abbreviations.
+func TestLabelDataSource_WorkspaceDelimiter(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testProviderConfig + `
+provider "label" {
+  tenant              = "dpl"
+  environment         = "ane2"
+  stage               = "dev"
+  workspace           = "analytics.api"
+  workspace_delimiter = "."
+  workspace_abbreviations = {
+    analytics = "anl"
+  }
+}
+
+data "label" "db" {
+  resource_type = "db"
+  delimiter     = "_"
+}
+
+output "id" {
+  value = data.label.db.id
+}
+`,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownOutputValue("id", knownvalue.StringExact("dpl_ane2_db_dev_anl_api")),
+				},
+			},
+		},
+	})
+}
+
+func TestLabelDataSource_Attributes(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testProviderConfig + `
+provider "label" {
+  tenant                = "dpl"
+  environment           = "ane2"
+  stage                 = "dev"
+  workspace             = "sales-api"
+  attributes_delimiter  = "."
+  attributes_components = ["resource_type", "qualifier"]
+}
+
+data "label" "sg" {
+  resource_type = "sg"
+  qualifier     = "emr"
+}
+
+output "attributes" {
+  value = data.label.sg.attributes
+}
+
+output "attributes_tag" {
+  value = data.label.sg.tags["Attributes"]
+}
+`,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownOutputValue("attributes", knownvalue.ListExact([]knownvalue.Check{
+						knownvalue.StringExact("sg"),
+						knownvalue.StringExact("emr"),
+					})),
+					statecheck.ExpectKnownOutputValue("attributes_tag", knownvalue.StringExact("sg.emr")),
+				},
+			},
+		},
+	})
+}
+
+func TestLabelDataSource_AmbiguityCheck(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testProviderConfig + `
+provider "label" {
+  tenant          = "dpl"
+  environment     = "ane2"
+  stage           = "dev"
+  workspace       = "api"
+  ambiguity_check = "error"
+}
+
+data "label" "sg" {
+  resource_type = "sg"
+  qualifier     = "emr-sales"
+}
+`,
+				ExpectError: regexp.MustCompile(`qualifier = "emr-sales"`),
+			},
+		},
+	})
+}
+
+func TestLabelDataSource_EscapeDelimiter(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testProviderConfig + `
+provider "label" {
+  tenant           = "dpl"
+  environment      = "ane2"
+  stage            = "dev"
+  workspace        = "sales-api"
+  ambiguity_check  = "error"
+  escape_delimiter = true
+}
+
+data "label" "sg" {
+  resource_type = "sg"
+  qualifier     = "emr"
+}
+
+output "id" {
+  value = data.label.sg.id
+}
+`,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownOutputValue("id", knownvalue.StringExact("dpl-ane2-sg-dev-emr-sales--api")),
+				},
+			},
+		},
+	})
+}
+
+func TestLabelDataSource_DuplicateCheck(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testProviderConfig + `
+provider "label" {
+  tenant          = "dpl"
+  environment     = "ane2"
+  stage           = "dev"
+  workspace       = "sales-api"
+  duplicate_check = "error"
+}
+
+data "label" "emr" {
+  resource_type = "sg"
+  qualifier     = "emr"
+}
+
+data "label" "emr_copy" {
+  resource_type = "sg"
+  qualifier     = "emr"
+}
+`,
+				ExpectError: regexp.MustCompile(`Duplicate Label ID`),
+			},
+		},
+	})
+}
+
+func TestLabelDataSource_UniqueSuffix(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testProviderConfig + `
+provider "label" {
+  tenant      = "dpl"
+  environment = "ane2"
+  stage       = "dev"
+  workspace   = "sales-api"
+  suffix_salt = "acme"
+}
+
+data "label" "logs" {
+  resource_type = "s3"
+  qualifier     = "logs"
+  unique_suffix = true
+}
+
+output "id" {
+  value = data.label.logs.id
+}
+
+output "suffix" {
+  value = data.label.logs.suffix
+}
+`,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownOutputValue("id", knownvalue.StringExact("dpl-ane2-s3-dev-logs-sales-api-5a45vm")),
+					statecheck.ExpectKnownOutputValue("suffix", knownvalue.StringExact("5a45vm")),
+				},
+			},
+		},
+	})
+}
+
+func TestLabelDataSource_LegacyNames(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testProviderConfig + `
+provider "label" {
+  tenant      = "dpl"
+  environment = "ane2"
+  stage       = "dev"
+  workspace   = "sales-api"
+  legacy_names = {
+    "dpl-ane2-sg-dev-emr-sales-api" = "emr-master-sg"
+    "orders-db"                     = "ordersprod"
+  }
+}
+
+data "label" "sg" {
+  resource_type = "sg"
+  qualifier     = "emr"
+}
+
+data "label" "db" {
+  resource_type = "db"
+  legacy_key    = "orders-db"
+}
+
+data "label" "role" {
+  resource_type = "role"
+}
+
+output "sg_id" {
+  value = data.label.sg.id
+}
+
+output "sg_name_tag" {
+  value = data.label.sg.tags["Name"]
+}
+
+output "db_id" {
+  value = data.label.db.id
+}
+
+output "role_is_legacy" {
+  value = data.label.role.is_legacy
+}
+`,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownOutputValue("sg_id", knownvalue.StringExact("emr-master-sg")),
+					statecheck.ExpectKnownOutputValue("sg_name_tag", knownvalue.StringExact("dpl-ane2-sg-dev-emr-sales-api")),
+					statecheck.ExpectKnownOutputValue("db_id", knownvalue.StringExact("ordersprod")),
+					statecheck.ExpectKnownOutputValue("role_is_legacy", knownvalue.Bool(false)),
+				},
+			},
+		},
+	})
+}
+
+func TestLabelDataSource_NullLabelCompat(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testProviderConfig + `
+provider "label" {
+  compat         = "null-label"
+  namespace      = "eg"
+  stage          = "prod"
+  label_key_case = "upper"
+}
+
+data "label" "bastion" {
+  resource_type = "ec2"
+  qualifier     = "bastion"
+  attributes    = ["public"]
+  instance_key  = "a"
+}
+
+data "label" "short" {
+  resource_type   = "lb"
+  qualifier       = "winston churchroom"
+  attributes      = ["fire", "water", "earth", "air"]
+  id_length_limit = 20
+}
+
+output "id" {
+  value = data.label.bastion.id
+}
+
+output "tags" {
+  value = data.label.bastion.tags_without_name
+}
+
+output "attributes" {
+  value = data.label.bastion.attributes
+}
+
+output "short_id" {
+  value = data.label.short.id
+}
+`,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownOutputValue("id", knownvalue.StringExact("eg-prod-bastion-public-a")),
+					statecheck.ExpectKnownOutputValue("tags", knownvalue.MapExact(map[string]knownvalue.Check{
+						"NAMESPACE":  knownvalue.StringExact("eg"),
+						"STAGE":      knownvalue.StringExact("prod"),
+						"ATTRIBUTES": knownvalue.StringExact("public-a"),
+					})),
+					statecheck.ExpectKnownOutputValue("attributes", knownvalue.ListExact([]knownvalue.Check{
+						knownvalue.StringExact("public"),
+						knownvalue.StringExact("a"),
+					})),
+					statecheck.ExpectKnownOutputValue("short_id", knownvalue.StringExact("eg-prod-winsto-fb2ff")),
+				},
+			},
+		},
+	})
+}
+
+func TestLabelDataSource_NullLabelSettingWithoutCompat(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testProviderConfigWithValues + `
+data "label" "test" {
+  resource_type = "sg"
+  attributes    = ["public"]
+}
+`,
+				ExpectError: regexp.MustCompile(`Null-label Setting Without Compatibility Mode`),
+			},
+		},
+	})
+}
+
+func TestLabelDataSource_NullLabelContext(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testProviderConfig + `
+provider "label" {
+  compat    = "null-label"
+  namespace = "eg"
+  stage     = "prod"
+}
+
+data "label" "app" {
+  resource_type = "ec2"
+  context_json = jsonencode({
+    namespace      = "cp"
+    environment    = "ue2"
+    name           = "app"
+    attributes     = ["blue"]
+    tags           = { Team = "data" }
+    label_order    = ["namespace", "environment", "name", "attributes"]
+    label_key_case = "upper"
+  })
+  attributes = ["db"]
+}
+
+data "label" "next" {
+  resource_type = "ec2"
+  context_json  = jsonencode(data.label.app.null_label_context)
+}
+
+output "id" {
+  value = data.label.app.id
+}
+
+output "team" {
+  value = data.label.app.tags["Team"]
+}
+
+output "context_name" {
+  value = data.label.app.null_label_context.name
+}
+
+output "next_id" {
+  value = data.label.next.id
+}
+`,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownOutputValue("id", knownvalue.StringExact("cp-ue2-app-blue-db")),
+					statecheck.ExpectKnownOutputValue("team", knownvalue.StringExact("data")),
+					statecheck.ExpectKnownOutputValue("context_name", knownvalue.StringExact("app")),
+					statecheck.ExpectKnownOutputValue("next_id", knownvalue.StringExact("cp-ue2-app-blue-db")),
+				},
+			},
+		},
+	})
+}
+
+func TestLabelDataSource_NullLabelContextWithoutCompat(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testProviderConfigWithValues + `
+data "label" "test" {
+  resource_type = "sg"
+  context_json  = jsonencode({ name = "emr", stage = "prd", attributes = ["a", "b"], tags = { Team = "data" } })
+}
+
+output "id" {
+  value = data.label.test.id
+}
+
+output "team" {
+  value = data.label.test.tags["Team"]
+}
+
+output "context_label_order" {
+  value = data.label.test.null_label_context.label_order
+}
+`,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownOutputValue("id", knownvalue.StringExact("dpl-ane2-sg-prd-emr-sales-api-a-b")),
+					statecheck.ExpectKnownOutputValue("team", knownvalue.StringExact("data")),
+					statecheck.ExpectKnownOutputValue("context_label_order", knownvalue.ListExact([]knownvalue.Check{
+						knownvalue.StringExact("tenant"),
+						knownvalue.StringExact("environment"),
+						knownvalue.StringExact("stage"),
+						knownvalue.StringExact("name"),
+						knownvalue.StringExact("attributes"),
+					})),
+				},
+			},
+		},
+	})
+}
@@ -0,0 +1,177 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ datasource.DataSource = (*RegionDataSource)(nil)
+
+type RegionDataSource struct {
+	config *LabelConfig
+}
+
+type RegionDataSourceModel struct {
+	Region    types.String `tfsdk:"region"`
+	Code      types.String `tfsdk:"code"`
+	Cloud     types.String `tfsdk:"cloud"`
+	Style     types.String `tfsdk:"style"`
+	ShortCode types.String `tfsdk:"short_code"`
+	FixedCode types.String `tfsdk:"fixed_code"`
+}
+
+func NewRegionDataSource() datasource.DataSource {
+	return &RegionDataSource{}
+}
+
+func (d *RegionDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_region"
+}
+
+func (d *RegionDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Converts between cloud region names and their abbreviations.",
+		Attributes: map[string]schema.Attribute{
+			"region": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "Region name (e.g. ap-northeast-2). Exactly one of region or code must be set.",
+			},
+			"code": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "Region abbreviation (e.g. ane2). Exactly one of region or code must be set.",
+			},
+			"cloud": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "Cloud the region belongs to: aws, gcp or azure. Used to resolve code (default: aws) and, when set, must match the cloud of region.",
+			},
+			"style": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "Abbreviation style returned in code: short or fixed (default: provider region_style, else short). When code is set, the style of that code.",
+			},
+			"short_code": schema.StringAttribute{
+				Computed:    true,
+				Description: "Short abbreviation (e.g. ane2)",
+			},
+			"fixed_code": schema.StringAttribute{
+				Computed:    true,
+				Description: "Fixed-length, three character abbreviation (e.g. an2)",
+			},
+		},
+	}
+}
+
+func (d *RegionDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	cfg, ok := req.ProviderData.(*LabelConfig)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Provider Data",
+			fmt.Sprintf("Expected *LabelConfig, got: %T", req.ProviderData),
+		)
+		return
+	}
+
+	d.config = cfg
+}
+
+func (d *RegionDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var model RegionDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var table *RegionTable
+	style := RegionStyleShort
+	if d.config != nil {
+		table = d.config.Regions
+		if d.config.RegionStyle != "" {
+			style = d.config.RegionStyle
+		}
+	}
+	if !model.Style.IsNull() {
+		style = model.Style.ValueString()
+	}
+	cloud := "aws"
+	if !model.Cloud.IsNull() {
+		cloud = model.Cloud.ValueString()
+	}
+	// Configured values are kept as they are; the lookup only fills in the
+	// others.
+	codeStyle := ""
+	if !model.Style.IsNull() {
+		codeStyle = style
+	}
+
+	if model.Region.IsNull() == model.Code.IsNull() {
+		resp.Diagnostics.AddError(
+			"Invalid Region Lookup",
+			"Exactly one of region or code must be set.",
+		)
+		return
+	}
+	if err := validateRegionStyle(style); err != nil {
+		resp.Diagnostics.AddError("Invalid Region Style", err.Error())
+		return
+	}
+
+	var entry RegionCode
+	var ok bool
+	if !model.Region.IsNull() {
+		entry, ok = table.Lookup(model.Region.ValueString())
+		if !ok {
+			resp.Diagnostics.AddError(
+				"Unknown Region",
+				fmt.Sprintf("Region %q is not in the region table. Add it to the provider region_codes map.", model.Region.ValueString()),
+			)
+			return
+		}
+	} else {
+		if err := validateRegionCloud(cloud); err != nil {
+			resp.Diagnostics.AddError("Invalid Cloud", err.Error())
+			return
+		}
+		entry, style, ok = table.ReverseStyle(model.Code.ValueString(), cloud, codeStyle)
+		if !ok {
+			detail := fmt.Sprintf("Code %q does not match any %s region.", model.Code.ValueString(), cloud)
+			if codeStyle != "" {
+				detail = fmt.Sprintf("Code %q does not match any %s region in the %s style.", model.Code.ValueString(), cloud, codeStyle)
+			}
+			resp.Diagnostics.AddError("Unknown Region Code", detail)
+			return
+		}
+	}
+
+	if entry.Cloud != "custom" {
+		if !model.Cloud.IsNull() && entry.Cloud != cloud {
+			resp.Diagnostics.AddError(
+				"Invalid Cloud",
+				fmt.Sprintf("Region %q belongs to %s, not %s.", entry.Region, entry.Cloud, cloud),
+			)
+			return
+		}
+		cloud = entry.Cloud
+	}
+
+	model.Region = types.StringValue(entry.Region)
+	if model.Code.IsNull() {
+		model.Code = types.StringValue(entry.Code(style))
+	}
+	model.Cloud = types.StringValue(cloud)
+	model.Style = types.StringValue(style)
+	model.ShortCode = types.StringValue(entry.Short)
+	model.FixedCode = types.StringValue(entry.Fixed)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &model)...)
+}
@@ -0,0 +1,192 @@
+package provider
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+)
+
+func TestRegionDataSource_FromRegion(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testProviderConfig + `
+data "label_region" "test" {
+  region = "ap-northeast-2"
+}
+
+output "code" {
+  value = data.label_region.test.code
+}
+
+output "fixed_code" {
+  value = data.label_region.test.fixed_code
+}
+
+output "cloud" {
+  value = data.label_region.test.cloud
+}
+`,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownOutputValue("code", knownvalue.StringExact("ane2")),
+					statecheck.ExpectKnownOutputValue("fixed_code", knownvalue.StringExact("an2")),
+					statecheck.ExpectKnownOutputValue("cloud", knownvalue.StringExact("aws")),
+				},
+			},
+		},
+	})
+}
+
+func TestRegionDataSource_FromCode(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testProviderConfig + `
+data "label_region" "test" {
+  code  = "ane3"
+  cloud = "gcp"
+}
+
+output "region" {
+  value = data.label_region.test.region
+}
+`,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownOutputValue("region", knownvalue.StringExact("asia-northeast3")),
+				},
+			},
+		},
+	})
+}
+
+func TestRegionDataSource_FromFixedCode(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testProviderConfig + `
+data "label_region" "test" {
+  code = "an2"
+}
+
+output "code" {
+  value = data.label_region.test.code
+}
+
+output "style" {
+  value = data.label_region.test.style
+}
+
+output "region" {
+  value = data.label_region.test.region
+}
+`,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownOutputValue("code", knownvalue.StringExact("an2")),
+					statecheck.ExpectKnownOutputValue("style", knownvalue.StringExact("fixed")),
+					statecheck.ExpectKnownOutputValue("region", knownvalue.StringExact("ap-northeast-2")),
+				},
+			},
+		},
+	})
+}
+
+func TestRegionDataSource_CloudMismatch(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testProviderConfig + `
+data "label_region" "test" {
+  region = "ap-northeast-2"
+  cloud  = "gcp"
+}
+`,
+				ExpectError: regexp.MustCompile(`Invalid Cloud`),
+			},
+		},
+	})
+}
+
+func TestRegionDataSource_Unknown(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testProviderConfig + `
+data "label_region" "test" {
+  region = "mars-north-1"
+}
+`,
+				ExpectError: regexp.MustCompile(`Unknown Region`),
+			},
+		},
+	})
+}
+
+// TestLabelDataSource_EnvironmentFromRegion tests deriving environment from the provider region.
+func TestLabelDataSource_EnvironmentFromRegion(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testProviderConfig + `
+provider "label" {
+  tenant    = "dpl"
+  region    = "ap-northeast-2"
+  stage     = "dev"
+  workspace = "sales-api"
+}
+
+data "label" "test" {
+  resource_type = "sg"
+}
+
+output "id" {
+  value = data.label.test.id
+}
+`,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownOutputValue("id", knownvalue.StringExact("dpl-ane2-sg-dev-sales-api")),
+				},
+			},
+		},
+	})
+}
+
+func TestRegionFunctions(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_8_0),
+		},
+		ProtoV6ProviderFactories: testProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testProviderConfig + `
+output "code" {
+  value = provider::label::region_code("ap-northeast-2")
+}
+
+output "fixed" {
+  value = provider::label::region_code("us-east-1", "fixed")
+}
+
+output "region" {
+  value = provider::label::region_name("krc", "azure")
+}
+`,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownOutputValue("code", knownvalue.StringExact("ane2")),
+					statecheck.ExpectKnownOutputValue("fixed", knownvalue.StringExact("ue1")),
+					statecheck.ExpectKnownOutputValue("region", knownvalue.StringExact("koreacentral")),
+				},
+			},
+		},
+	})
+}
@@ -0,0 +1,101 @@
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Detector derives naming components from an environment variable set by a
+// CI/CD platform.
+type Detector struct {
+	Name   string
+	EnvVar string
+}
+
+// KnownDetectors are the built-in detectors, keyed by name.
+var KnownDetectors = map[string]Detector{
+	"tfc":       {Name: "tfc", EnvVar: "TFC_WORKSPACE_NAME"},
+	"scalr":     {Name: "scalr", EnvVar: "SCALR_WORKSPACE_NAME"},
+	"spacelift": {Name: "spacelift", EnvVar: "SPACELIFT_STACK_ID"},
+	"env0":      {Name: "env0", EnvVar: "ENV0_ENVIRONMENT_NAME"},
+	"atlantis":  {Name: "atlantis", EnvVar: "PROJECT_NAME"},
+	"terraform": {Name: "terraform", EnvVar: "TF_WORKSPACE"},
+}
+
+// DefaultDetectorOrder is the chain used when detection is enabled without an
+// explicit order. TF_WORKSPACE is last because it is the least specific.
+var DefaultDetectorOrder = []string{"tfc", "scalr", "spacelift", "env0", "atlantis", "terraform"}
+
+// Detection records which detector matched and the components it derived.
+type Detection struct {
+	Detector   Detector
+	Value      string
+	Components map[string]string
+}
+
+// Source describes the detection for provenance records,
+// e.g. "detector tfc (TFC_WORKSPACE_NAME)".
+func (d *Detection) Source() string {
+	return fmt.Sprintf("detector %s (%s)", d.Detector.Name, d.Detector.EnvVar)
+}
+
+// ParseDetectors resolves detector names to detectors. Besides the names in
+// KnownDetectors, "env:<VAR>" reads an arbitrary variable and "default"
+// expands to DefaultDetectorOrder.
+func ParseDetectors(names []string) ([]Detector, error) {
+	var out []Detector
+	for _, name := range names {
+		switch {
+		case name == "default":
+			for _, n := range DefaultDetectorOrder {
+				out = append(out, KnownDetectors[n])
+			}
+		case strings.HasPrefix(name, "env:"):
+			envVar := strings.TrimPrefix(name, "env:")
+			if envVar == "" {
+				return nil, fmt.Errorf("detector %q is missing an environment variable name", name)
+			}
+			out = append(out, Detector{Name: name, EnvVar: envVar})
+		default:
+			d, ok := KnownDetectors[name]
+			if !ok {
+				return nil, fmt.Errorf("unknown detector %q; use one of %s, default, or env:<VAR>", name, strings.Join(DefaultDetectorOrder, ", "))
+			}
+			out = append(out, d)
+		}
+	}
+	return out, nil
+}
+
+// Detect walks the detector chain in order and returns the first detector
+// whose variable is set and, when pattern is non-nil, matches it. Named
+// groups in pattern become components (e.g. (?P<workspace>...),
+// (?P<stage>...)); without a pattern the whole value is the workspace.
+// A nil result means no detector matched.
+func Detect(detectors []Detector, pattern *regexp.Regexp, getenv func(string) string) *Detection {
+	for _, d := range detectors {
+		value := getenv(d.EnvVar)
+		if value == "" {
+			continue
+		}
+
+		components := map[string]string{}
+		if pattern == nil {
+			components["workspace"] = value
+		} else {
+			match := pattern.FindStringSubmatch(value)
+			if match == nil {
+				continue
+			}
+			for i, name := range pattern.SubexpNames() {
+				if name != "" && match[i] != "" {
+					components[name] = match[i]
+				}
+			}
+		}
+
+		return &Detection{Detector: d, Value: value, Components: components}
+	}
+	return nil
+}
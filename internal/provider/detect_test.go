@@ -0,0 +1,104 @@
+package provider
+
+import (
+	"regexp"
+	"testing"
+)
+
+func TestParseDetectors(t *testing.T) {
+	got, err := ParseDetectors([]string{"scalr", "env:CI_PROJECT_NAME", "terraform"})
+	if err != nil {
+		t.Fatalf("ParseDetectors() error: %v", err)
+	}
+	want := []string{"SCALR_WORKSPACE_NAME", "CI_PROJECT_NAME", "TF_WORKSPACE"}
+	if len(got) != len(want) {
+		t.Fatalf("ParseDetectors() = %v, want env vars %v", got, want)
+	}
+	for i := range got {
+		if got[i].EnvVar != want[i] {
+			t.Errorf("detector[%d].EnvVar = %q, want %q", i, got[i].EnvVar, want[i])
+		}
+	}
+
+	all, err := ParseDetectors([]string{"default"})
+	if err != nil || len(all) != len(DefaultDetectorOrder) {
+		t.Errorf("ParseDetectors(default) = %v, %v", all, err)
+	}
+
+	if _, err := ParseDetectors([]string{"jenkins"}); err == nil {
+		t.Error("expected error for unknown detector")
+	}
+	if _, err := ParseDetectors([]string{"env:"}); err == nil {
+		t.Error("expected error for empty env detector")
+	}
+}
+
+func TestDetect(t *testing.T) {
+	detectors, _ := ParseDetectors([]string{"default"})
+	stagePattern := regexp.MustCompile(`^(?P<workspace>.+)-(?P<stage>dev|stg|prd)$`)
+
+	tests := []struct {
+		name         string
+		env          map[string]string
+		pattern      *regexp.Regexp
+		wantDetector string
+		want         map[string]string
+	}{
+		{
+			name: "nothing set",
+			env:  map[string]string{},
+		},
+		{
+			name:         "terraform cloud",
+			env:          map[string]string{"TFC_WORKSPACE_NAME": "sales-api", "TF_WORKSPACE": "default"},
+			wantDetector: "tfc",
+			want:         map[string]string{"workspace": "sales-api"},
+		},
+		{
+			name:         "chain order",
+			env:          map[string]string{"SPACELIFT_STACK_ID": "hr-web", "TF_WORKSPACE": "other"},
+			wantDetector: "spacelift",
+			want:         map[string]string{"workspace": "hr-web"},
+		},
+		{
+			name:         "pattern extracts stage",
+			env:          map[string]string{"SCALR_WORKSPACE_NAME": "sales-api-prd"},
+			pattern:      stagePattern,
+			wantDetector: "scalr",
+			want:         map[string]string{"workspace": "sales-api", "stage": "prd"},
+		},
+		{
+			name:         "pattern mismatch falls through",
+			env:          map[string]string{"TFC_WORKSPACE_NAME": "sandbox", "TF_WORKSPACE": "vpc-dev"},
+			pattern:      stagePattern,
+			wantDetector: "terraform",
+			want:         map[string]string{"workspace": "vpc", "stage": "dev"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Detect(detectors, tt.pattern, func(key string) string { return tt.env[key] })
+			if tt.wantDetector == "" {
+				if got != nil {
+					t.Fatalf("Detect() = %+v, want nil", got)
+				}
+				return
+			}
+			if got == nil {
+				t.Fatal("Detect() = nil")
+			}
+			if got.Detector.Name != tt.wantDetector {
+				t.Errorf("Detector = %q, want %q", got.Detector.Name, tt.wantDetector)
+			}
+			if len(got.Components) != len(tt.want) {
+				t.Fatalf("Components = %v, want %v", got.Components, tt.want)
+			}
+			for k, v := range tt.want {
+				if got.Components[k] != v {
+					t.Errorf("Components[%q] = %q, want %q", k, got.Components[k], v)
+				}
+			}
+		})
+	}
+}
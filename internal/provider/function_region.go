@@ -0,0 +1,131 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+)
+
+var (
+	_ function.Function = (*RegionCodeFunction)(nil)
+	_ function.Function = (*RegionNameFunction)(nil)
+)
+
+// RegionCodeFunction converts a region name to its abbreviation using the
+// built-in region table. Provider functions do not receive the provider
+// configuration, so region_codes entries are only known to the label_region
+// data source.
+type RegionCodeFunction struct{}
+
+func NewRegionCodeFunction() function.Function {
+	return &RegionCodeFunction{}
+}
+
+func (f *RegionCodeFunction) Metadata(_ context.Context, _ function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "region_code"
+}
+
+func (f *RegionCodeFunction) Definition(_ context.Context, _ function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:     "Converts a region name to its abbreviation",
+		Description: "Returns the abbreviation of an AWS, GCP or Azure region name (e.g. ap-northeast-2 → ane2). An optional second argument selects the style: short (default) or fixed. Only the built-in region table is used: entries of the provider region_codes map are not available to functions, so use the label_region data source for them.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "region",
+				Description: "Region name (e.g. ap-northeast-2, asia-northeast3, koreacentral)",
+			},
+		},
+		VariadicParameter: function.StringParameter{
+			Name:        "style",
+			Description: "Abbreviation style: short or fixed",
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *RegionCodeFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var region string
+	var options []string
+	resp.Error = function.ConcatFuncErrors(resp.Error, req.Arguments.Get(ctx, &region, &options))
+	if resp.Error != nil {
+		return
+	}
+
+	style := RegionStyleShort
+	if len(options) > 0 {
+		style = options[0]
+	}
+
+	if err := validateRegionStyle(style); err != nil {
+		resp.Error = function.NewFuncError(err.Error())
+		return
+	}
+	var builtin *RegionTable
+	entry, ok := builtin.Lookup(region)
+	if !ok {
+		resp.Error = function.NewFuncError(fmt.Sprintf("unknown region %q; region_code only knows the built-in regions, use the label_region data source for region_codes entries", region))
+		return
+	}
+	code := entry.Code(style)
+
+	resp.Error = function.ConcatFuncErrors(resp.Error, resp.Result.Set(ctx, code))
+}
+
+// RegionNameFunction converts an abbreviation back to a region name using the
+// built-in region table; see RegionCodeFunction.
+type RegionNameFunction struct{}
+
+func NewRegionNameFunction() function.Function {
+	return &RegionNameFunction{}
+}
+
+func (f *RegionNameFunction) Metadata(_ context.Context, _ function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "region_name"
+}
+
+func (f *RegionNameFunction) Definition(_ context.Context, _ function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:     "Converts a region abbreviation to its region name",
+		Description: "Returns the region name for a short or fixed abbreviation (e.g. ane2 → ap-northeast-2). An optional second argument selects the cloud: aws (default), gcp or azure. Only the built-in region table is used: entries of the provider region_codes map are not available to functions, so use the label_region data source for them.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "code",
+				Description: "Region abbreviation (e.g. ane2)",
+			},
+		},
+		VariadicParameter: function.StringParameter{
+			Name:        "cloud",
+			Description: "Cloud the region belongs to: aws, gcp or azure",
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *RegionNameFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var code string
+	var options []string
+	resp.Error = function.ConcatFuncErrors(resp.Error, req.Arguments.Get(ctx, &code, &options))
+	if resp.Error != nil {
+		return
+	}
+
+	cloud := "aws"
+	if len(options) > 0 {
+		cloud = options[0]
+	}
+
+	if err := validateRegionCloud(cloud); err != nil {
+		resp.Error = function.NewFuncError(err.Error())
+		return
+	}
+	var builtin *RegionTable
+	entry, ok := builtin.Reverse(code, cloud)
+	if !ok {
+		resp.Error = function.NewFuncError(fmt.Sprintf("unknown %s region code %q; region_name only knows the built-in regions, use the label_region data source for region_codes entries", cloud, code))
+		return
+	}
+	region := entry.Region
+
+	resp.Error = function.ConcatFuncErrors(resp.Error, resp.Result.Set(ctx, region))
+}
@@ -1,6 +1,9 @@
 package provider
 
 import (
+	"fmt"
+	"maps"
+	"slices"
 	"strings"
 )
 
@@ -12,6 +15,365 @@ type LabelConfig struct {
 	Workspace   string
 	Namespace   string // optional, used in tags only
 	Delimiter   string // default "-", override via LABEL_DELIMITER
+
+	// WorkspaceDelimiter is the set of characters the workspace is split on
+	// (default "-"). Each character is a separator on its own.
+	WorkspaceDelimiter string
+
+	// WorkspaceOpaque keeps the workspace as a single segment.
+	WorkspaceOpaque bool
+
+	// WorkspaceAbbreviations replaces individual workspace segments,
+	// e.g. {"analytics": "anl"}.
+	WorkspaceAbbreviations map[string]string
+
+	// AttributesDelimiter joins the Attributes tag (default "-").
+	AttributesDelimiter string
+
+	// AttributesComponents lists the components that make up the Attributes
+	// tag. Defaults to DefaultAttributesComponents.
+	AttributesComponents []string
+
+	// OmitAttributesTag suppresses the Attributes tag.
+	OmitAttributesTag bool
+
+	// AmbiguityCheck reports components that contain the delimiter:
+	// CheckOff (default), CheckWarn or CheckError.
+	AmbiguityCheck string
+
+	// DuplicateCheck reports IDs generated more than once in a run:
+	// CheckOff, CheckWarn (default) or CheckError. IDs are recorded in
+	// Registry, which is shared by every clone of the config.
+	DuplicateCheck string
+	Registry       *IDRegistry
+
+	// SuffixSalt is added to the seed of every unique suffix.
+	SuffixSalt string
+
+	// LegacyNames maps a computed ID or a stable key to a name that predates
+	// the convention and is returned instead of the computed ID.
+	LegacyNames map[string]string
+
+	// EscapeDelimiter renders each component as one segment and doubles any
+	// delimiter inside it, so IDs can be split again with ParseID.
+	EscapeDelimiter bool
+
+	// Components holds custom naming components (e.g. account, region)
+	// keyed by name. Each is emitted as a tag and may appear in LabelOrder.
+	Components map[string]string
+
+	// LabelOrder is the order of components in generated identifiers.
+	// Defaults to DefaultLabelOrder.
+	LabelOrder []string
+
+	// Region is the cloud region name the environment may be derived from,
+	// and RegionStyle the abbreviation style used for it.
+	Region      string
+	RegionStyle string
+
+	// Regions is the region abbreviation table including provider-level
+	// region_codes. A nil table uses the built-in codes only.
+	Regions *RegionTable
+
+	// Sources records where each provider-level component value came from,
+	// e.g. "config", "env LABEL_STAGE" or "detector tfc (TFC_WORKSPACE_NAME)".
+	Sources map[string]string
+
+	// Aliases maps a component name to alternative spellings of its value
+	// and the canonical value they normalize to,
+	// e.g. {"stage": {"production": "prd", "prod": "prd"}}.
+	Aliases map[string]map[string]string
+
+	// RawValues holds the pre-alias value of each component that was
+	// normalized by ApplyAliases.
+	RawValues map[string]string
+
+	// RawValueTags adds a "<Key>Raw" tag with the pre-alias value of each
+	// normalized component.
+	RawValueTags bool
+
+	// RequiredComponents lists the components that must be non-empty before
+	// an identifier is generated. Defaults to DefaultRequiredComponents.
+	RequiredComponents []string
+
+	// Compat selects how IDs and tags are generated: CompatNone (default)
+	// or CompatNullLabel, which reproduces terraform-null-label with the
+	// qualifier as its name and the instance key as its attribute.
+	Compat string
+
+	// NullLabel holds the null-label inputs used with CompatNullLabel.
+	NullLabel NullLabelOptions
+}
+
+// DefaultRequiredComponents are enforced when required_components is not set.
+var DefaultRequiredComponents = []string{"tenant", "environment", "stage"}
+
+// BuiltinComponents are the provider-level components known to LabelConfig.
+var BuiltinComponents = []string{"tenant", "environment", "stage", "workspace", "namespace"}
+
+// ResourceComponents are supplied per data source rather than by the provider.
+var ResourceComponents = []string{"resource_type", "qualifier", "instance_key"}
+
+// DefaultLabelOrder is the identifier order used when label_order is not set.
+var DefaultLabelOrder = []string{"tenant", "environment", "resource_type", "stage", "qualifier", "workspace", "instance_key"}
+
+// DefaultAttributesComponents make up the Attributes tag when
+// attributes_components is not set.
+var DefaultAttributesComponents = []string{"qualifier", "workspace", "instance_key"}
+
+// Check modes for ambiguity_check and duplicate_check.
+const (
+	CheckOff   = "off"   // no check
+	CheckWarn  = "warn"  // report findings as a warning
+	CheckError = "error" // fail the read
+)
+
+func validateCheckMode(mode string) error {
+	switch mode {
+	case CheckOff, CheckWarn, CheckError:
+		return nil
+	}
+	return fmt.Errorf("invalid check mode %q; expected %q, %q or %q", mode, CheckOff, CheckWarn, CheckError)
+}
+
+// labelInputs are the per-resource values supplied by a data source.
+type labelInputs struct {
+	resourceType string
+	qualifier    string
+	instanceKey  string
+}
+
+// Component returns the value of a named provider-level component, built-in
+// or custom. The second result is false when the name is not configured.
+func (c *LabelConfig) Component(name string) (string, bool) {
+	switch name {
+	case "tenant":
+		return c.Tenant, true
+	case "environment":
+		return c.Environment, true
+	case "stage":
+		return c.Stage, true
+	case "workspace":
+		return c.Workspace, true
+	case "namespace":
+		return c.Namespace, true
+	}
+	v, ok := c.Components[name]
+	return v, ok
+}
+
+// Clone returns a deep copy of the config so a single read can override
+// values without affecting the provider-level defaults.
+func (c *LabelConfig) Clone() *LabelConfig {
+	out := *c
+	out.WorkspaceAbbreviations = maps.Clone(c.WorkspaceAbbreviations)
+	out.Components = maps.Clone(c.Components)
+	out.RawValues = maps.Clone(c.RawValues)
+	out.Sources = maps.Clone(c.Sources)
+	out.LegacyNames = maps.Clone(c.LegacyNames)
+	out.LabelOrder = slices.Clone(c.LabelOrder)
+	out.AttributesComponents = slices.Clone(c.AttributesComponents)
+	out.RequiredComponents = slices.Clone(c.RequiredComponents)
+	out.NullLabel.LabelsAsTags = slices.Clone(c.NullLabel.LabelsAsTags)
+	return &out
+}
+
+// ComponentSources returns the sources of the components that have a value,
+// e.g. {"stage": "env LABEL_STAGE", "workspace": "detector tfc (TFC_WORKSPACE_NAME)"}.
+func (c *LabelConfig) ComponentSources() map[string]string {
+	out := map[string]string{}
+	for name, source := range c.Sources {
+		if v, _ := c.Component(name); v != "" {
+			out[name] = source
+		}
+	}
+	return out
+}
+
+// WithOverrides returns a copy of the config with the given components set
+// and aliases applied, e.g. for per-read overrides.
+func (c *LabelConfig) WithOverrides(overrides map[string]string) *LabelConfig {
+	out := c.Clone()
+	for name, value := range overrides {
+		out.SetComponent(name, value)
+	}
+	out.ApplyAliases()
+	return out
+}
+
+// LegacyName returns the legacy name registered for key, or else for id.
+// An empty key is ignored.
+func (c *LabelConfig) LegacyName(key string, id string) (string, bool) {
+	if key != "" {
+		if name, ok := c.LegacyNames[key]; ok {
+			return name, true
+		}
+	}
+	name, ok := c.LegacyNames[id]
+	return name, ok
+}
+
+// IsLegacyName reports whether name is one of the registered legacy names.
+func (c *LabelConfig) IsLegacyName(name string) bool {
+	for _, legacy := range c.LegacyNames {
+		if legacy == name {
+			return true
+		}
+	}
+	return false
+}
+
+// SetComponent sets the value of a provider-level component, built-in or
+// custom, and forgets any pre-alias value recorded for it.
+func (c *LabelConfig) SetComponent(name string, value string) {
+	switch name {
+	case "tenant":
+		c.Tenant = value
+	case "environment":
+		c.Environment = value
+	case "stage":
+		c.Stage = value
+	case "workspace":
+		c.Workspace = value
+	case "namespace":
+		c.Namespace = value
+	default:
+		if c.Components == nil {
+			c.Components = map[string]string{}
+		}
+		c.Components[name] = value
+	}
+	delete(c.RawValues, name)
+}
+
+// ApplyAliases replaces component values with their canonical form from
+// Aliases. Exact matches win; otherwise aliases match case-insensitively.
+// The replaced values are recorded in RawValues.
+func (c *LabelConfig) ApplyAliases() {
+	for name, aliases := range c.Aliases {
+		value, ok := c.Component(name)
+		if !ok || value == "" {
+			continue
+		}
+
+		canonical, found := aliases[value]
+		if !found {
+			keys := slices.Sorted(maps.Keys(aliases))
+			for _, alias := range keys {
+				if strings.EqualFold(alias, value) {
+					canonical, found = aliases[alias], true
+					break
+				}
+			}
+		}
+		if !found || canonical == value {
+			continue
+		}
+
+		c.SetComponent(name, canonical)
+		if c.RawValues == nil {
+			c.RawValues = map[string]string{}
+		}
+		c.RawValues[name] = value
+	}
+}
+
+// MissingComponents returns the required components that have no value, in
+// the order they were declared.
+func (c *LabelConfig) MissingComponents() []string {
+	required := c.RequiredComponents
+	if required == nil {
+		required = DefaultRequiredComponents
+	}
+
+	var missing []string
+	for _, name := range required {
+		if v, _ := c.Component(name); v == "" {
+			missing = append(missing, name)
+		}
+	}
+	return missing
+}
+
+func (c *LabelConfig) labelOrder() []string {
+	if c.LabelOrder == nil {
+		return DefaultLabelOrder
+	}
+	return c.LabelOrder
+}
+
+// WorkspaceSegments splits the workspace according to WorkspaceDelimiter and
+// WorkspaceOpaque, then applies WorkspaceAbbreviations to each segment.
+func (c *LabelConfig) WorkspaceSegments() []string {
+	var segments []string
+	switch {
+	case c.Workspace == "":
+		return nil
+	case c.WorkspaceOpaque:
+		segments = []string{c.Workspace}
+	case c.WorkspaceDelimiter == "":
+		segments = SplitWorkspace(c.Workspace)
+	default:
+		segments = SplitWorkspaceBy(c.Workspace, c.WorkspaceDelimiter)
+	}
+
+	for i, seg := range segments {
+		if abbr, ok := c.WorkspaceAbbreviations[seg]; ok {
+			segments[i] = abbr
+		}
+	}
+	return segments
+}
+
+// segments returns the identifier segments contributed by a single component.
+// The workspace expands to one segment per WorkspaceSegments element.
+func (c *LabelConfig) segments(name string, in labelInputs) []string {
+	var v string
+	switch name {
+	case "resource_type":
+		v = in.resourceType
+	case "qualifier":
+		v = in.qualifier
+	case "instance_key":
+		v = in.instanceKey
+	case "workspace":
+		return c.WorkspaceSegments()
+	default:
+		v, _ = c.Component(name)
+	}
+	if v == "" {
+		return nil
+	}
+	return []string{v}
+}
+
+// IsCustomComponent reports whether name is a custom component rather than
+// a built-in provider-level or per-resource one.
+func IsCustomComponent(name string) bool {
+	return !slices.Contains(BuiltinComponents, name) && !slices.Contains(ResourceComponents, name)
+}
+
+// ComponentEnvVar returns the environment variable a component falls back to.
+// Custom components use the LABEL_COMPONENT_ prefix.
+func ComponentEnvVar(name string) string {
+	if IsCustomComponent(name) {
+		return "LABEL_COMPONENT_" + strings.ToUpper(name)
+	}
+	return "LABEL_" + strings.ToUpper(name)
+}
+
+// ComponentTagKey converts a component name to its tag key.
+// e.g. "cost_center" → "CostCenter", "region" → "Region"
+func ComponentTagKey(name string) string {
+	var b strings.Builder
+	for _, word := range strings.Split(name, "_") {
+		if word == "" {
+			continue
+		}
+		b.WriteString(strings.ToUpper(word[:1]))
+		b.WriteString(word[1:])
+	}
+	return b.String()
 }
 
 // SplitWorkspace splits the workspace name by "-" into segments.
@@ -23,65 +385,116 @@ func SplitWorkspace(workspace string) []string {
 	return strings.Split(workspace, "-")
 }
 
-// GenerateID builds an identifier string from the label components.
-// Order: {tenant}{d}{environment}{d}{resource_type}{d}{stage}{d}{qualifier}{d}{workspace}{d}{instance_key}
-// Empty segments are skipped.
+// SplitWorkspaceBy splits the workspace name on any of the characters in
+// delimiters. Like SplitWorkspace it keeps empty segments, so that
+// "sales--api" is not renamed to "sales-api".
+// e.g. ("eks-v1_34", "-_") → ["eks", "v1", "34"], ("sales.api", ".") → ["sales", "api"]
+func SplitWorkspaceBy(workspace string, delimiters string) []string {
+	if workspace == "" {
+		return nil
+	}
+	var segments []string
+	start := 0
+	for i, r := range workspace {
+		if strings.ContainsRune(delimiters, r) {
+			segments = append(segments, workspace[start:i])
+			start = i + len(string(r))
+		}
+	}
+	return append(segments, workspace[start:])
+}
+
+// GenerateID builds an identifier string from the label components in
+// cfg.LabelOrder. The default order is:
+// {tenant}{d}{environment}{d}{resource_type}{d}{stage}{d}{qualifier}{d}{workspace}{d}{instance_key}
+// Empty segments are skipped. With cfg.EscapeDelimiter, delimiters inside a
+// component are escaped (see ParseID). With CompatNullLabel the ID is that
+// of terraform-null-label (see NullLabel.Generate).
 func GenerateID(cfg *LabelConfig, resourceType string, qualifier string, instanceKey string, delimiter string) string {
+	if cfg.Compat == CompatNullLabel {
+		return NullLabelFor(cfg, qualifier, []string{instanceKey}, delimiter).Generate().ID
+	}
 	if delimiter == "" {
 		delimiter = cfg.Delimiter
 	}
 
-	ws := SplitWorkspace(cfg.Workspace)
+	in := labelInputs{resourceType: resourceType, qualifier: qualifier, instanceKey: instanceKey}
 
-	parts := []string{
-		cfg.Tenant,
-		cfg.Environment,
-		resourceType,
-		cfg.Stage,
+	var parts []string
+	for _, name := range cfg.labelOrder() {
+		segs := cfg.segments(name, in)
+		if cfg.EscapeDelimiter && len(segs) > 0 {
+			segs = []string{EscapeSegment(strings.Join(segs, delimiter), delimiter)}
+		}
+		parts = append(parts, segs...)
 	}
 
-	if qualifier != "" {
-		parts = append(parts, qualifier)
+	return strings.Join(parts, delimiter)
+}
+
+// GenerateAttributes returns the segments of the Attributes tag, taken from
+// cfg.AttributesComponents in order. Empty segments are skipped.
+func GenerateAttributes(cfg *LabelConfig, resourceType string, qualifier string, instanceKey string) []string {
+	if cfg.Compat == CompatNullLabel {
+		return NullLabelFor(cfg, qualifier, []string{instanceKey}, "").Generate().Attributes
+	}
+	components := cfg.AttributesComponents
+	if components == nil {
+		components = DefaultAttributesComponents
 	}
 
-	parts = append(parts, ws...)
+	in := labelInputs{resourceType: resourceType, qualifier: qualifier, instanceKey: instanceKey}
 
-	if instanceKey != "" {
-		parts = append(parts, instanceKey)
+	var attrs []string
+	for _, name := range components {
+		attrs = append(attrs, cfg.segments(name, in)...)
 	}
-
-	return strings.Join(parts, delimiter)
+	return attrs
 }
 
 // GenerateTags builds a tag map for the resource.
+// Custom components with a value are added under their ComponentTagKey.
+// With CompatNullLabel the tags are those of terraform-null-label.
 func GenerateTags(cfg *LabelConfig, resourceType string, qualifier string, instanceKey string, delimiter string) map[string]string {
+	if cfg.Compat == CompatNullLabel {
+		return NullLabelFor(cfg, qualifier, []string{instanceKey}, delimiter).Generate().Tags
+	}
 	name := GenerateID(cfg, resourceType, qualifier, instanceKey, delimiter)
 
-	ws := SplitWorkspace(cfg.Workspace)
-
-	var attrParts []string
-	if qualifier != "" {
-		attrParts = append(attrParts, qualifier)
+	attrDelimiter := cfg.AttributesDelimiter
+	if attrDelimiter == "" {
+		attrDelimiter = "-"
 	}
-	attrParts = append(attrParts, ws...)
-	if instanceKey != "" {
-		attrParts = append(attrParts, instanceKey)
-	}
-
-	attributes := strings.Join(attrParts, "-")
+	attributes := strings.Join(GenerateAttributes(cfg, resourceType, qualifier, instanceKey), attrDelimiter)
 
 	tags := map[string]string{
-		"Name":        name,
+		"Name": name,
+	}
+
+	for key, value := range map[string]string{
 		"Tenant":      cfg.Tenant,
 		"Environment": cfg.Environment,
 		"Stage":       cfg.Stage,
+		"Namespace":   cfg.Namespace,
+	} {
+		if value != "" {
+			tags[key] = value
+		}
+	}
+
+	for component, value := range cfg.Components {
+		if value != "" {
+			tags[ComponentTagKey(component)] = value
+		}
 	}
 
-	if cfg.Namespace != "" {
-		tags["Namespace"] = cfg.Namespace
+	if cfg.RawValueTags {
+		for component, raw := range cfg.RawValues {
+			tags[ComponentTagKey(component)+"Raw"] = raw
+		}
 	}
 
-	if attributes != "" {
+	if attributes != "" && !cfg.OmitAttributesTag {
 		tags["Attributes"] = attributes
 	}
 
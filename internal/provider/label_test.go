@@ -1,6 +1,9 @@
 package provider
 
 import (
+	"maps"
+	"slices"
+	"strings"
 	"testing"
 )
 
@@ -15,6 +18,8 @@ func TestSplitWorkspace(t *testing.T) {
 		{"eks-v1_34", []string{"eks", "v1_34"}},
 		{"rds-postgres", []string{"rds", "postgres"}},
 		{"sales-api-orders", []string{"sales", "api", "orders"}},
+		{"sales--api", []string{"sales", "", "api"}},
+		{"-vpc", []string{"", "vpc"}},
 		{"", nil},
 	}
 
@@ -124,6 +129,12 @@ func TestGenerateID_DifferentWorkspaces(t *testing.T) {
 			resourceType: "node",
 			want:         "dpl-ane2-node-dev-eks-v1_34",
 		},
+		{
+			name:         "workspace with empty segment",
+			workspace:    "a--b",
+			resourceType: "sg",
+			want:         "dpl-ane2-sg-dev-a--b",
+		},
 		{
 			name:         "empty workspace",
 			workspace:    "",
@@ -506,3 +517,507 @@ func TestGenerateTags_RealWorldPatterns(t *testing.T) {
 		})
 	}
 }
+
+func TestMissingComponents(t *testing.T) {
+	tests := []struct {
+		name     string
+		cfg      LabelConfig
+		required []string
+		want     []string
+	}{
+		{
+			name: "defaults satisfied",
+			cfg:  LabelConfig{Tenant: "dpl", Environment: "ane2", Stage: "dev"},
+		},
+		{
+			name: "defaults missing all",
+			cfg:  LabelConfig{},
+			want: []string{"tenant", "environment", "stage"},
+		},
+		{
+			name:     "workspace required",
+			cfg:      LabelConfig{Tenant: "dpl", Environment: "ane2", Stage: "dev"},
+			required: []string{"tenant", "workspace"},
+			want:     []string{"workspace"},
+		},
+		{
+			name:     "custom component required",
+			cfg:      LabelConfig{Components: map[string]string{"account": ""}},
+			required: []string{"account", "region"},
+			want:     []string{"account", "region"},
+		},
+		{
+			name:     "nothing required",
+			cfg:      LabelConfig{},
+			required: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.cfg.RequiredComponents = tt.required
+			got := tt.cfg.MissingComponents()
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("MissingComponents() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerateID_CustomComponents(t *testing.T) {
+	cfg := &LabelConfig{
+		Tenant:      "dpl",
+		Environment: "ane2",
+		Stage:       "dev",
+		Workspace:   "sales-api",
+		Delimiter:   "-",
+		Components: map[string]string{
+			"account":     "core",
+			"cost_center": "cc42",
+		},
+	}
+
+	tests := []struct {
+		name  string
+		order []string
+		want  string
+	}{
+		{
+			name: "default order ignores custom components",
+			want: "dpl-ane2-sg-dev-emr-sales-api",
+		},
+		{
+			name:  "custom component placed in order",
+			order: []string{"tenant", "account", "environment", "resource_type", "stage", "qualifier", "workspace", "instance_key"},
+			want:  "dpl-core-ane2-sg-dev-emr-sales-api",
+		},
+		{
+			name:  "reordered built-in components",
+			order: []string{"resource_type", "qualifier", "stage", "workspace"},
+			want:  "sg-emr-dev-sales-api",
+		},
+		{
+			name:  "undeclared custom component is skipped",
+			order: []string{"tenant", "region", "resource_type"},
+			want:  "dpl-sg",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg.LabelOrder = tt.order
+			got := GenerateID(cfg, "sg", "emr", "", "")
+			if got != tt.want {
+				t.Errorf("GenerateID() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerateTags_CustomComponents(t *testing.T) {
+	cfg := &LabelConfig{
+		Tenant:      "dpl",
+		Environment: "ane2",
+		Stage:       "dev",
+		Delimiter:   "-",
+		Components: map[string]string{
+			"account":     "core",
+			"cost_center": "cc42",
+			"domain":      "",
+		},
+	}
+
+	tags := GenerateTags(cfg, "sg", "", "", "")
+
+	if tags["Account"] != "core" {
+		t.Errorf("Account = %q, want %q", tags["Account"], "core")
+	}
+	if tags["CostCenter"] != "cc42" {
+		t.Errorf("CostCenter = %q, want %q", tags["CostCenter"], "cc42")
+	}
+	if _, ok := tags["Domain"]; ok {
+		t.Error("Domain should not be present when the component is empty")
+	}
+}
+
+func TestComponentEnvVar(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"tenant", "LABEL_TENANT"},
+		{"workspace", "LABEL_WORKSPACE"},
+		{"account", "LABEL_COMPONENT_ACCOUNT"},
+		{"cost_center", "LABEL_COMPONENT_COST_CENTER"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ComponentEnvVar(tt.name); got != tt.want {
+				t.Errorf("ComponentEnvVar(%q) = %q, want %q", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLabelConfig_Clone(t *testing.T) {
+	cfg := &LabelConfig{
+		Tenant:      "dpl",
+		Environment: "ane2",
+		Stage:       "dev",
+		Workspace:   "sales-api",
+		Delimiter:   "-",
+		Components:  map[string]string{"account": "core"},
+		LabelOrder:  []string{"tenant", "resource_type", "stage"},
+	}
+
+	clone := cfg.Clone()
+	clone.Stage = "shared"
+	clone.Workspace = ""
+	clone.Components["account"] = "sec"
+	clone.LabelOrder[0] = "account"
+
+	if got := GenerateID(cfg, "kms", "", "", ""); got != "dpl-kms-dev" {
+		t.Errorf("original GenerateID() = %q, want %q", got, "dpl-kms-dev")
+	}
+	if got := GenerateID(clone, "kms", "", "", ""); got != "sec-kms-shared" {
+		t.Errorf("clone GenerateID() = %q, want %q", got, "sec-kms-shared")
+	}
+}
+
+func TestApplyAliases(t *testing.T) {
+	aliases := map[string]map[string]string{
+		"stage": {
+			"production":  "prd",
+			"prod":        "prd",
+			"development": "dev",
+		},
+		"account": {
+			"security": "sec",
+		},
+	}
+
+	tests := []struct {
+		name      string
+		stage     string
+		account   string
+		wantStage string
+		wantRaw   map[string]string
+	}{
+		{
+			name:      "exact alias",
+			stage:     "production",
+			wantStage: "prd",
+			wantRaw:   map[string]string{"stage": "production"},
+		},
+		{
+			name:      "case-insensitive alias",
+			stage:     "Development",
+			wantStage: "dev",
+			wantRaw:   map[string]string{"stage": "Development"},
+		},
+		{
+			name:      "canonical value unchanged",
+			stage:     "prd",
+			wantStage: "prd",
+			wantRaw:   map[string]string{},
+		},
+		{
+			name:      "custom component alias",
+			stage:     "dev",
+			account:   "security",
+			wantStage: "dev",
+			wantRaw:   map[string]string{"account": "security"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &LabelConfig{
+				Tenant:      "dpl",
+				Environment: "ane2",
+				Stage:       tt.stage,
+				Delimiter:   "-",
+				Components:  map[string]string{"account": tt.account},
+				Aliases:     aliases,
+			}
+			cfg.ApplyAliases()
+
+			if cfg.Stage != tt.wantStage {
+				t.Errorf("Stage = %q, want %q", cfg.Stage, tt.wantStage)
+			}
+			if len(cfg.RawValues) != len(tt.wantRaw) {
+				t.Fatalf("RawValues = %v, want %v", cfg.RawValues, tt.wantRaw)
+			}
+			for k, v := range tt.wantRaw {
+				if cfg.RawValues[k] != v {
+					t.Errorf("RawValues[%q] = %q, want %q", k, cfg.RawValues[k], v)
+				}
+			}
+		})
+	}
+}
+
+func TestGenerateTags_RawValueTags(t *testing.T) {
+	cfg := &LabelConfig{
+		Tenant:      "dpl",
+		Environment: "ane2",
+		Stage:       "production",
+		Workspace:   "sales-api",
+		Delimiter:   "-",
+		Aliases:     map[string]map[string]string{"stage": {"production": "prd"}},
+	}
+	cfg.ApplyAliases()
+
+	tags := GenerateTags(cfg, "sg", "", "", "")
+	if tags["Name"] != "dpl-ane2-sg-prd-sales-api" {
+		t.Errorf("Name = %q", tags["Name"])
+	}
+	if _, ok := tags["StageRaw"]; ok {
+		t.Error("StageRaw should not be present unless RawValueTags is set")
+	}
+
+	cfg.RawValueTags = true
+	tags = GenerateTags(cfg, "sg", "", "", "")
+	if tags["Stage"] != "prd" {
+		t.Errorf("Stage = %q, want %q", tags["Stage"], "prd")
+	}
+	if tags["StageRaw"] != "production" {
+		t.Errorf("StageRaw = %q, want %q", tags["StageRaw"], "production")
+	}
+
+	cfg.SetComponent("stage", "stg")
+	tags = GenerateTags(cfg, "sg", "", "", "")
+	if _, ok := tags["StageRaw"]; ok {
+		t.Error("StageRaw should be cleared when the stage is set directly")
+	}
+}
+
+func TestSplitWorkspaceBy(t *testing.T) {
+	tests := []struct {
+		workspace  string
+		delimiters string
+		want       []string
+	}{
+		{"sales-api", "-", []string{"sales", "api"}},
+		{"sales.api", ".", []string{"sales", "api"}},
+		{"sales.api", "-", []string{"sales.api"}},
+		{"eks-v1_34", "-_", []string{"eks", "v1", "34"}},
+		{"sales-api.orders", "-.", []string{"sales", "api", "orders"}},
+		{"sales--api", "-", []string{"sales", "", "api"}},
+		{"eks-_v1", "-_", []string{"eks", "", "v1"}},
+		{"", "-", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.workspace+"/"+tt.delimiters, func(t *testing.T) {
+			got := SplitWorkspaceBy(tt.workspace, tt.delimiters)
+			if len(got) != len(tt.want) {
+				t.Fatalf("SplitWorkspaceBy(%q, %q) = %v, want %v", tt.workspace, tt.delimiters, got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("SplitWorkspaceBy(%q, %q)[%d] = %q, want %q", tt.workspace, tt.delimiters, i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestGenerateID_WorkspaceHandling(t *testing.T) {
+	tests := []struct {
+		name           string
+		workspace      string
+		wsDelimiter    string
+		opaque         bool
+		abbreviations  map[string]string
+		delimiter      string
+		want           string
+		wantAttributes string
+	}{
+		{
+			name:           "dot-separated workspace",
+			workspace:      "sales.api",
+			wsDelimiter:    ".",
+			delimiter:      "_",
+			want:           "dpl_ane2_sg_dev_sales_api",
+			wantAttributes: "sales-api",
+		},
+		{
+			name:           "dot workspace with default split stays whole",
+			workspace:      "sales.api",
+			delimiter:      "_",
+			want:           "dpl_ane2_sg_dev_sales.api",
+			wantAttributes: "sales.api",
+		},
+		{
+			name:           "multiple workspace delimiters",
+			workspace:      "eks-v1_34",
+			wsDelimiter:    "-_",
+			want:           "dpl-ane2-sg-dev-eks-v1-34",
+			wantAttributes: "eks-v1-34",
+		},
+		{
+			name:           "opaque workspace",
+			workspace:      "eks-v1_34",
+			opaque:         true,
+			delimiter:      "_",
+			want:           "dpl_ane2_sg_dev_eks-v1_34",
+			wantAttributes: "eks-v1_34",
+		},
+		{
+			name:           "segment abbreviations",
+			workspace:      "analytics-platform-api",
+			abbreviations:  map[string]string{"analytics": "anl", "platform": "plt"},
+			want:           "dpl-ane2-sg-dev-anl-plt-api",
+			wantAttributes: "anl-plt-api",
+		},
+		{
+			name:           "abbreviation of opaque workspace",
+			workspace:      "sales-api",
+			opaque:         true,
+			abbreviations:  map[string]string{"sales-api": "sapi"},
+			want:           "dpl-ane2-sg-dev-sapi",
+			wantAttributes: "sapi",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &LabelConfig{
+				Tenant:                 "dpl",
+				Environment:            "ane2",
+				Stage:                  "dev",
+				Workspace:              tt.workspace,
+				Delimiter:              "-",
+				WorkspaceDelimiter:     tt.wsDelimiter,
+				WorkspaceOpaque:        tt.opaque,
+				WorkspaceAbbreviations: tt.abbreviations,
+			}
+			got := GenerateID(cfg, "sg", "", "", tt.delimiter)
+			if got != tt.want {
+				t.Errorf("GenerateID() = %q, want %q", got, tt.want)
+			}
+			tags := GenerateTags(cfg, "sg", "", "", tt.delimiter)
+			if tags["Attributes"] != tt.wantAttributes {
+				t.Errorf("Attributes = %q, want %q", tags["Attributes"], tt.wantAttributes)
+			}
+		})
+	}
+}
+
+func TestGenerateAttributes(t *testing.T) {
+	tests := []struct {
+		name           string
+		delimiter      string
+		components     []string
+		custom         map[string]string
+		omit           bool
+		want           []string
+		wantAttributes string
+	}{
+		{
+			name:           "defaults",
+			want:           []string{"emr", "sales", "api", "01"},
+			wantAttributes: "emr-sales-api-01",
+		},
+		{
+			name:           "custom delimiter",
+			delimiter:      ".",
+			want:           []string{"emr", "sales", "api", "01"},
+			wantAttributes: "emr.sales.api.01",
+		},
+		{
+			name:           "resource type and custom component",
+			delimiter:      "_",
+			components:     []string{"resource_type", "account", "qualifier"},
+			custom:         map[string]string{"account": "core"},
+			want:           []string{"sg", "core", "emr"},
+			wantAttributes: "sg_core_emr",
+		},
+		{
+			name:           "unset components are skipped",
+			components:     []string{"namespace", "qualifier"},
+			want:           []string{"emr"},
+			wantAttributes: "emr",
+		},
+		{
+			name:           "tag omitted",
+			omit:           true,
+			want:           []string{"emr", "sales", "api", "01"},
+			wantAttributes: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &LabelConfig{
+				Tenant:               "dpl",
+				Environment:          "ane2",
+				Stage:                "dev",
+				Workspace:            "sales-api",
+				Delimiter:            "-",
+				AttributesDelimiter:  tt.delimiter,
+				AttributesComponents: tt.components,
+				OmitAttributesTag:    tt.omit,
+				Components:           tt.custom,
+			}
+			got := GenerateAttributes(cfg, "sg", "emr", "01")
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("GenerateAttributes() = %v, want %v", got, tt.want)
+			}
+			tags := GenerateTags(cfg, "sg", "emr", "01", "")
+			if tags["Attributes"] != tt.wantAttributes {
+				t.Errorf("Attributes = %q, want %q", tags["Attributes"], tt.wantAttributes)
+			}
+		})
+	}
+}
+
+func TestLabelConfig_LegacyName(t *testing.T) {
+	cfg := &LabelConfig{
+		LegacyNames: map[string]string{
+			"dpl-ane2-sg-dev-emr-sales-api": "emr-master-sg",
+			"orders-db":                     "ordersprod",
+		},
+	}
+
+	tests := []struct {
+		key    string
+		id     string
+		want   string
+		wantOK bool
+	}{
+		{"", "dpl-ane2-sg-dev-emr-sales-api", "emr-master-sg", true},
+		{"orders-db", "dpl-ane2-db-dev-sales-api", "ordersprod", true},
+		{"orders-db", "dpl-ane2-sg-dev-emr-sales-api", "ordersprod", true},
+		{"unknown", "dpl-ane2-sg-dev-emr-sales-api", "emr-master-sg", true},
+		{"", "dpl-ane2-db-dev-sales-api", "", false},
+	}
+
+	for _, tt := range tests {
+		got, ok := cfg.LegacyName(tt.key, tt.id)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("LegacyName(%q, %q) = %q, %v, want %q, %v", tt.key, tt.id, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestComponentSources(t *testing.T) {
+	cfg := &LabelConfig{
+		Tenant:     "dpl",
+		Stage:      "dev",
+		Components: map[string]string{"team": "data"},
+		Sources: map[string]string{
+			"tenant":    "config",
+			"stage":     "env LABEL_STAGE",
+			"workspace": "detector tfc (TFC_WORKSPACE_NAME)",
+			"team":      "path stacks/{team}/*",
+		},
+	}
+	// The workspace was overridden to empty, so its source no longer applies.
+	want := map[string]string{"tenant": "config", "stage": "env LABEL_STAGE", "team": "path stacks/{team}/*"}
+	if got := cfg.ComponentSources(); !maps.Equal(got, want) {
+		t.Errorf("ComponentSources() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,534 @@
+package provider
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// Compatibility modes for compat.
+const (
+	CompatNone      = "none"       // the provider's own convention
+	CompatNullLabel = "null-label" // reproduce cloudposse/terraform-null-label
+)
+
+// Letter cases for label_key_case and label_value_case.
+const (
+	CaseLower = "lower"
+	CaseTitle = "title"
+	CaseUpper = "upper"
+	CaseNone  = "none" // values only
+)
+
+// NullLabelOrder is the label_order of terraform-null-label 0.25, and
+// NullLabelLabels every label it knows.
+var (
+	NullLabelOrder  = []string{"namespace", "environment", "stage", "name", "attributes"}
+	NullLabelLabels = []string{"namespace", "tenant", "environment", "stage", "name", "attributes"}
+)
+
+// null-label defaults.
+const (
+	DefaultRegexReplaceChars  = "/[^-a-zA-Z0-9]/"
+	DefaultNullLabelKeyCase   = CaseTitle
+	DefaultNullLabelValueCase = CaseLower
+
+	nullLabelHashLength        = 5
+	nullLabelHashPadding       = "qrstuvwxyz"
+	minNullLabelIDLengthLimit  = 6
+	defaultNullLabelsAsTagsKey = "default"
+)
+
+// NullLabelOptions are the null-label inputs that have no counterpart in the
+// provider's own convention. They are only used with CompatNullLabel.
+type NullLabelOptions struct {
+	// IDLengthLimit truncates longer IDs and appends a hash. 0 is unlimited.
+	IDLengthLimit int
+
+	// RegexReplaceChars is removed from every label. A value wrapped in
+	// slashes is a regular expression. Defaults to DefaultRegexReplaceChars.
+	RegexReplaceChars string
+
+	// LabelKeyCase and LabelValueCase set the letter case of tag keys and
+	// label values. Default to title and lower.
+	LabelKeyCase   string
+	LabelValueCase string
+
+	// LabelsAsTags lists the labels emitted as tags. Nil means all of them.
+	LabelsAsTags []string
+}
+
+// NullLabel holds the inputs of one terraform-null-label module.
+type NullLabel struct {
+	Namespace   string
+	Tenant      string
+	Environment string
+	Stage       string
+	Name        string
+	Attributes  []string
+	Delimiter   string
+	LabelOrder  []string
+	Tags        map[string]string
+	NullLabelOptions
+}
+
+// NullLabelOutput holds the outputs of a terraform-null-label module.
+type NullLabelOutput struct {
+	ID          string
+	IDFull      string
+	Namespace   string
+	Tenant      string
+	Environment string
+	Stage       string
+	Name        string
+	Attributes  []string
+	Tags        map[string]string
+}
+
+// NullLabelFor returns the null-label inputs that correspond to a label:
+// qualifier is the null-label name and attributes its attributes. Tenant,
+// environment, stage and namespace come from cfg.
+func NullLabelFor(cfg *LabelConfig, qualifier string, attributes []string, delimiter string) NullLabel {
+	if delimiter == "" {
+		delimiter = cfg.Delimiter
+	}
+	return NullLabel{
+		Namespace:        cfg.Namespace,
+		Tenant:           cfg.Tenant,
+		Environment:      cfg.Environment,
+		Stage:            cfg.Stage,
+		Name:             qualifier,
+		Attributes:       attributes,
+		Delimiter:        delimiter,
+		LabelOrder:       cfg.LabelOrder,
+		NullLabelOptions: cfg.NullLabel,
+	}
+}
+
+// Generate computes the module outputs the way terraform-null-label does,
+// byte for byte. Lengths count characters, where Terraform counts grapheme
+// clusters; the two agree unless regex_replace_chars keeps combining marks.
+func (n NullLabel) Generate() NullLabelOutput {
+	keyCase := n.LabelKeyCase
+	if keyCase == "" {
+		keyCase = DefaultNullLabelKeyCase
+	}
+	valueCase := n.LabelValueCase
+	if valueCase == "" {
+		valueCase = DefaultNullLabelValueCase
+	}
+	order := n.LabelOrder
+	if len(order) == 0 {
+		order = NullLabelOrder
+	}
+	replace := nullLabelReplacer(n.RegexReplaceChars)
+	format := func(v string) string {
+		return nullLabelCase(replace(v), valueCase)
+	}
+
+	var normalized []string
+	for _, a := range n.Attributes {
+		normalized = append(normalized, replace(a))
+	}
+	var attributes []string
+	for _, a := range compactDistinct(normalized) {
+		attributes = append(attributes, nullLabelCase(a, valueCase))
+	}
+	attributes = compactDistinct(attributes)
+
+	out := NullLabelOutput{
+		Namespace:   format(n.Namespace),
+		Tenant:      format(n.Tenant),
+		Environment: format(n.Environment),
+		Stage:       format(n.Stage),
+		Name:        format(n.Name),
+		Attributes:  attributes,
+	}
+	labels := map[string]string{
+		"namespace":   out.Namespace,
+		"tenant":      out.Tenant,
+		"environment": out.Environment,
+		"stage":       out.Stage,
+		"name":        out.Name,
+		"attributes":  strings.Join(attributes, n.Delimiter),
+	}
+
+	var parts []string
+	for _, label := range order {
+		if v := labels[label]; v != "" {
+			parts = append(parts, v)
+		}
+	}
+	out.IDFull = strings.Join(parts, n.Delimiter)
+	out.ID = out.IDFull
+
+	if limit := n.IDLengthLimit; limit != 0 && runeLen(out.IDFull) > limit {
+		var truncated string
+		if keep := limit - (nullLabelHashLength + len(n.Delimiter)); keep > 0 {
+			truncated = strings.TrimSuffix(substr(out.IDFull, keep), n.Delimiter) + n.Delimiter
+		}
+		sum := md5.Sum([]byte(out.IDFull))
+		hash := nullLabelCase(hex.EncodeToString(sum[:])+nullLabelHashPadding, valueCase)
+		out.ID = substr(truncated+replace(hash), limit)
+	}
+
+	// Name carries the ID; the other tags carry the formatted labels.
+	labels["name"] = out.ID
+	asTags := n.LabelsAsTags
+	if asTags == nil || slices.Contains(asTags, defaultNullLabelsAsTagsKey) {
+		asTags = NullLabelLabels
+	}
+	out.Tags = map[string]string{}
+	for _, label := range NullLabelLabels {
+		if v := labels[label]; v != "" && slices.Contains(asTags, label) {
+			out.Tags[nullLabelTagKey(label, keyCase)] = v
+		}
+	}
+	for k, v := range n.Tags {
+		out.Tags[k] = v
+	}
+	return out
+}
+
+// nullLabelReplacer returns a function that removes chars from a value like
+// Terraform's replace(): chars wrapped in slashes is a regular expression,
+// anything else a literal substring.
+func nullLabelReplacer(chars string) func(string) string {
+	if chars == "" {
+		chars = DefaultRegexReplaceChars
+	}
+	if len(chars) >= 2 && strings.HasPrefix(chars, "/") && strings.HasSuffix(chars, "/") {
+		re, err := regexp.Compile(chars[1 : len(chars)-1])
+		if err != nil {
+			// Rejected by ResolveConfig; leave values as they are.
+			return func(s string) string { return s }
+		}
+		return func(s string) string { return re.ReplaceAllLiteralString(s, "") }
+	}
+	return func(s string) string { return strings.ReplaceAll(s, chars, "") }
+}
+
+// nullLabelCase applies a label_value_case. title lowercases first, like
+// null-label's title(lower(v)).
+func nullLabelCase(v string, letterCase string) string {
+	switch letterCase {
+	case CaseNone:
+		return v
+	case CaseTitle:
+		return title(strings.ToLower(v))
+	case CaseUpper:
+		return strings.ToUpper(v)
+	}
+	return strings.ToLower(v)
+}
+
+// nullLabelTagKey applies a label_key_case to a label name.
+func nullLabelTagKey(label string, letterCase string) string {
+	switch letterCase {
+	case CaseUpper:
+		return strings.ToUpper(label)
+	case CaseLower:
+		return strings.ToLower(label)
+	}
+	return title(strings.ToLower(label))
+}
+
+// title upper-cases the first letter of each word like Terraform's title(),
+// which uses the word boundaries of the deprecated strings.Title.
+func title(s string) string {
+	prev := ' '
+	return strings.Map(func(r rune) rune {
+		start := isTitleSeparator(prev)
+		prev = r
+		if start {
+			return unicode.ToTitle(r)
+		}
+		return r
+	}, s)
+}
+
+// isTitleSeparator reports whether r ends a word for title.
+func isTitleSeparator(r rune) bool {
+	if r <= unicode.MaxASCII {
+		switch {
+		case '0' <= r && r <= '9', 'a' <= r && r <= 'z', 'A' <= r && r <= 'Z', r == '_':
+			return false
+		}
+		return true
+	}
+	if unicode.IsLetter(r) || unicode.IsDigit(r) {
+		return false
+	}
+	return unicode.IsSpace(r)
+}
+
+// compactDistinct removes empty and repeated values, keeping the first
+// occurrence, like Terraform's compact(distinct(list)).
+func compactDistinct(values []string) []string {
+	var out []string
+	for _, v := range values {
+		if v != "" && !slices.Contains(out, v) {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+func runeLen(s string) int {
+	return len([]rune(s))
+}
+
+// substr returns the first n characters of s.
+func substr(s string, n int) string {
+	r := []rune(s)
+	if n >= len(r) {
+		return s
+	}
+	return string(r[:n])
+}
+
+func validateCompat(mode string) error {
+	switch mode {
+	case CompatNone, CompatNullLabel:
+		return nil
+	}
+	return fmt.Errorf("invalid compat mode %q; expected %q or %q", mode, CompatNone, CompatNullLabel)
+}
+
+func validateLetterCase(letterCase string, allowNone bool) error {
+	switch letterCase {
+	case CaseLower, CaseTitle, CaseUpper:
+		return nil
+	case CaseNone:
+		if allowNone {
+			return nil
+		}
+	}
+	if allowNone {
+		return fmt.Errorf("invalid case %q; expected %q, %q, %q or %q", letterCase, CaseLower, CaseTitle, CaseUpper, CaseNone)
+	}
+	return fmt.Errorf("invalid case %q; expected %q, %q or %q", letterCase, CaseLower, CaseTitle, CaseUpper)
+}
+
+func validateIDLengthLimit(limit int) error {
+	if limit != 0 && limit < minNullLabelIDLengthLimit {
+		return fmt.Errorf("id_length_limit must be 0 (unlimited) or at least %d, got %d", minNullLabelIDLengthLimit, limit)
+	}
+	return nil
+}
+
+func validateRegexReplaceChars(chars string) error {
+	if len(chars) >= 2 && strings.HasPrefix(chars, "/") && strings.HasSuffix(chars, "/") {
+		if _, err := regexp.Compile(chars[1 : len(chars)-1]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// NullLabelContext is the context object that null-label modules pass to
+// each other as module.<name>.context. Nil fields are unset.
+type NullLabelContext struct {
+	Enabled           *bool                          `json:"enabled" tfsdk:"enabled"`
+	Namespace         *string                        `json:"namespace" tfsdk:"namespace"`
+	Tenant            *string                        `json:"tenant" tfsdk:"tenant"`
+	Environment       *string                        `json:"environment" tfsdk:"environment"`
+	Stage             *string                        `json:"stage" tfsdk:"stage"`
+	Name              *string                        `json:"name" tfsdk:"name"`
+	Delimiter         *string                        `json:"delimiter" tfsdk:"delimiter"`
+	Attributes        []string                       `json:"attributes" tfsdk:"attributes"`
+	Tags              map[string]string              `json:"tags" tfsdk:"tags"`
+	AdditionalTagMap  map[string]string              `json:"additional_tag_map" tfsdk:"additional_tag_map"`
+	LabelOrder        []string                       `json:"label_order" tfsdk:"label_order"`
+	RegexReplaceChars *string                        `json:"regex_replace_chars" tfsdk:"regex_replace_chars"`
+	IDLengthLimit     *int64                         `json:"id_length_limit" tfsdk:"id_length_limit"`
+	LabelKeyCase      *string                        `json:"label_key_case" tfsdk:"label_key_case"`
+	LabelValueCase    *string                        `json:"label_value_case" tfsdk:"label_value_case"`
+	LabelsAsTags      []string                       `json:"labels_as_tags" tfsdk:"labels_as_tags"`
+	DescriptorFormats map[string]NullLabelDescriptor `json:"descriptor_formats" tfsdk:"descriptor_formats"`
+}
+
+// NullLabelDescriptor is one entry of the null-label descriptor_formats.
+type NullLabelDescriptor struct {
+	Format string   `json:"format" tfsdk:"format"`
+	Labels []string `json:"labels" tfsdk:"labels"`
+}
+
+// nullLabelUnset is the labels_as_tags placeholder of a null-label context
+// that does not set it.
+const nullLabelUnset = "unset"
+
+// ParseNullLabelContext decodes a context passed through jsonencode and
+// checks the settings the label generation uses. Unknown keys, e.g. from
+// newer null-label versions, are ignored.
+func ParseNullLabelContext(data string) (*NullLabelContext, error) {
+	var c NullLabelContext
+	if err := json.Unmarshal([]byte(data), &c); err != nil {
+		return nil, fmt.Errorf("decoding context: %w", err)
+	}
+	for _, name := range c.LabelOrder {
+		if !slices.Contains(NullLabelLabels, name) {
+			return nil, fmt.Errorf("label_order: %q is not a null-label label", name)
+		}
+	}
+	if c.RegexReplaceChars != nil {
+		if err := validateRegexReplaceChars(*c.RegexReplaceChars); err != nil {
+			return nil, fmt.Errorf("regex_replace_chars: %w", err)
+		}
+	}
+	if c.IDLengthLimit != nil {
+		if err := validateIDLengthLimit(int(*c.IDLengthLimit)); err != nil {
+			return nil, err
+		}
+	}
+	if c.LabelKeyCase != nil {
+		if err := validateLetterCase(*c.LabelKeyCase, false); err != nil {
+			return nil, fmt.Errorf("label_key_case: %w", err)
+		}
+	}
+	if c.LabelValueCase != nil {
+		if err := validateLetterCase(*c.LabelValueCase, true); err != nil {
+			return nil, fmt.Errorf("label_value_case: %w", err)
+		}
+	}
+	if slices.Contains(c.LabelsAsTags, nullLabelUnset) {
+		c.LabelsAsTags = nil
+	}
+	return &c, nil
+}
+
+// Settings reports the null-label settings the context sets, in the order
+// of the context variable.
+func (c *NullLabelContext) Settings() []string {
+	var set []string
+	for _, s := range []struct {
+		name string
+		set  bool
+	}{
+		{"label_order", len(c.LabelOrder) > 0},
+		{"regex_replace_chars", c.RegexReplaceChars != nil},
+		{"id_length_limit", c.IDLengthLimit != nil},
+		{"label_key_case", c.LabelKeyCase != nil},
+		{"label_value_case", c.LabelValueCase != nil},
+		{"labels_as_tags", c.LabelsAsTags != nil},
+	} {
+		if s.set {
+			set = append(set, s.name)
+		}
+	}
+	return set
+}
+
+// Apply sets the null-label settings of the context on n, like null-label
+// does for a module that gets the context and leaves these variables unset.
+func (c *NullLabelContext) Apply(n *NullLabel) {
+	if len(c.LabelOrder) > 0 {
+		n.LabelOrder = c.LabelOrder
+	}
+	if c.RegexReplaceChars != nil {
+		n.RegexReplaceChars = *c.RegexReplaceChars
+	}
+	if c.IDLengthLimit != nil {
+		n.IDLengthLimit = int(*c.IDLengthLimit)
+	}
+	if c.LabelKeyCase != nil {
+		n.LabelKeyCase = *c.LabelKeyCase
+	}
+	if c.LabelValueCase != nil {
+		n.LabelValueCase = *c.LabelValueCase
+	}
+	if c.LabelsAsTags != nil {
+		n.LabelsAsTags = c.LabelsAsTags
+	}
+}
+
+// Context returns the context output of a null-label module with inputs n
+// and outputs out.
+func (n NullLabel) Context(out NullLabelOutput) NullLabelContext {
+	order := n.LabelOrder
+	if len(order) == 0 {
+		order = NullLabelOrder
+	}
+	asTags := n.LabelsAsTags
+	if asTags == nil || slices.Contains(asTags, defaultNullLabelsAsTagsKey) {
+		asTags = NullLabelLabels
+	}
+	regex := n.RegexReplaceChars
+	if regex == "" {
+		regex = DefaultRegexReplaceChars
+	}
+	keyCase := n.LabelKeyCase
+	if keyCase == "" {
+		keyCase = DefaultNullLabelKeyCase
+	}
+	valueCase := n.LabelValueCase
+	if valueCase == "" {
+		valueCase = DefaultNullLabelValueCase
+	}
+	limit := int64(n.IDLengthLimit)
+	enabled := true
+
+	return NullLabelContext{
+		Enabled:           &enabled,
+		Namespace:         &out.Namespace,
+		Tenant:            &out.Tenant,
+		Environment:       &out.Environment,
+		Stage:             &out.Stage,
+		Name:              &out.Name,
+		Delimiter:         &n.Delimiter,
+		Attributes:        append([]string{}, out.Attributes...),
+		Tags:              out.Tags,
+		AdditionalTagMap:  map[string]string{},
+		LabelOrder:        slices.Clone(order),
+		RegexReplaceChars: &regex,
+		IDLengthLimit:     &limit,
+		LabelKeyCase:      &keyCase,
+		LabelValueCase:    &valueCase,
+		LabelsAsTags:      slices.Clone(asTags),
+		DescriptorFormats: map[string]NullLabelDescriptor{},
+	}
+}
+
+// ConventionContext returns a null-label context for a label generated by
+// the provider's own convention, so null-label modules can continue from
+// it. The qualifier is the name, the instance key the only attribute, and
+// label_order keeps the components null-label knows.
+func ConventionContext(cfg *LabelConfig, qualifier string, instanceKey string, delimiter string, tags map[string]string) NullLabelContext {
+	if delimiter == "" {
+		delimiter = cfg.Delimiter
+	}
+	var order []string
+	for _, name := range cfg.labelOrder() {
+		switch name {
+		case "qualifier":
+			order = append(order, "name")
+		case "instance_key":
+			order = append(order, "attributes")
+		case "namespace", "tenant", "environment", "stage":
+			order = append(order, name)
+		}
+	}
+	enabled := true
+	namespace, tenant, environment, stage := cfg.Namespace, cfg.Tenant, cfg.Environment, cfg.Stage
+
+	return NullLabelContext{
+		Enabled:           &enabled,
+		Namespace:         &namespace,
+		Tenant:            &tenant,
+		Environment:       &environment,
+		Stage:             &stage,
+		Name:              &qualifier,
+		Delimiter:         &delimiter,
+		Attributes:        append([]string{}, compactDistinct([]string{instanceKey})...),
+		Tags:              tags,
+		AdditionalTagMap:  map[string]string{},
+		LabelOrder:        append([]string{}, order...),
+		LabelsAsTags:      []string{defaultNullLabelsAsTagsKey},
+		DescriptorFormats: map[string]NullLabelDescriptor{},
+	}
+}
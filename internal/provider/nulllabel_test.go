@@ -0,0 +1,234 @@
+package provider
+
+import (
+	"encoding/json"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// nullLabelGolden is one module call of the golden corpus in
+// testdata/null_label.json. The inputs use the variable names of
+// terraform-null-label and the outputs hold what the module returns.
+type nullLabelGolden struct {
+	Name   string `json:"name"`
+	Inputs struct {
+		Namespace         string            `json:"namespace"`
+		Tenant            string            `json:"tenant"`
+		Environment       string            `json:"environment"`
+		Stage             string            `json:"stage"`
+		Name              string            `json:"name"`
+		Attributes        []string          `json:"attributes"`
+		Delimiter         *string           `json:"delimiter"`
+		LabelOrder        []string          `json:"label_order"`
+		IDLengthLimit     int               `json:"id_length_limit"`
+		RegexReplaceChars string            `json:"regex_replace_chars"`
+		LabelKeyCase      string            `json:"label_key_case"`
+		LabelValueCase    string            `json:"label_value_case"`
+		LabelsAsTags      []string          `json:"labels_as_tags"`
+		Tags              map[string]string `json:"tags"`
+	} `json:"inputs"`
+	Outputs struct {
+		ID         string            `json:"id"`
+		IDFull     string            `json:"id_full"`
+		Attributes []string          `json:"attributes"`
+		Tags       map[string]string `json:"tags"`
+	} `json:"outputs"`
+}
+
+func TestNullLabel_Golden(t *testing.T) {
+	data, err := os.ReadFile("testdata/null_label.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var corpus []nullLabelGolden
+	if err := json.Unmarshal(data, &corpus); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range corpus {
+		t.Run(tt.Name, func(t *testing.T) {
+			in := tt.Inputs
+			delimiter := "-"
+			if in.Delimiter != nil {
+				delimiter = *in.Delimiter
+			}
+			got := NullLabel{
+				Namespace:   in.Namespace,
+				Tenant:      in.Tenant,
+				Environment: in.Environment,
+				Stage:       in.Stage,
+				Name:        in.Name,
+				Attributes:  in.Attributes,
+				Delimiter:   delimiter,
+				LabelOrder:  in.LabelOrder,
+				Tags:        in.Tags,
+				NullLabelOptions: NullLabelOptions{
+					IDLengthLimit:     in.IDLengthLimit,
+					RegexReplaceChars: in.RegexReplaceChars,
+					LabelKeyCase:      in.LabelKeyCase,
+					LabelValueCase:    in.LabelValueCase,
+					LabelsAsTags:      in.LabelsAsTags,
+				},
+			}.Generate()
+
+			want := tt.Outputs
+			if got.ID != want.ID {
+				t.Errorf("id = %q, want %q", got.ID, want.ID)
+			}
+			if got.IDFull != want.IDFull {
+				t.Errorf("id_full = %q, want %q", got.IDFull, want.IDFull)
+			}
+			if !slices.Equal(got.Attributes, want.Attributes) {
+				t.Errorf("attributes = %q, want %q", got.Attributes, want.Attributes)
+			}
+			if !maps.Equal(got.Tags, want.Tags) {
+				t.Errorf("tags = %v, want %v", got.Tags, want.Tags)
+			}
+		})
+	}
+}
+
+func TestGenerateID_NullLabel(t *testing.T) {
+	cfg := &LabelConfig{
+		Tenant:      "dpl",
+		Environment: "ane2",
+		Stage:       "dev",
+		Workspace:   "sales-api",
+		Namespace:   "acme",
+		Delimiter:   "-",
+		Compat:      CompatNullLabel,
+	}
+
+	if got, want := GenerateID(cfg, "sg", "emr", "public", ""), "acme-ane2-dev-emr-public"; got != want {
+		t.Errorf("GenerateID = %q, want %q", got, want)
+	}
+	if got, want := GenerateID(cfg, "sg", "emr", "", "_"), "acme_ane2_dev_emr"; got != want {
+		t.Errorf("GenerateID with delimiter = %q, want %q", got, want)
+	}
+
+	tags := GenerateTags(cfg, "sg", "emr", "public", "")
+	want := map[string]string{
+		"Namespace":   "acme",
+		"Tenant":      "dpl",
+		"Environment": "ane2",
+		"Stage":       "dev",
+		"Name":        "acme-ane2-dev-emr-public",
+		"Attributes":  "public",
+	}
+	if !maps.Equal(tags, want) {
+		t.Errorf("GenerateTags = %v, want %v", tags, want)
+	}
+}
+
+func TestTitle(t *testing.T) {
+	tests := map[string]string{
+		"hello world": "Hello World",
+		"my-app2x_v1": "My-App2x_v1",
+		"a.b/c":       "A.B/C",
+		"éclair café": "Éclair Café",
+		"":            "",
+	}
+	for in, want := range tests {
+		if got := title(in); got != want {
+			t.Errorf("title(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestParseNullLabelContext(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		wantErr string
+	}{
+		{
+			name: "module default context",
+			data: `{"enabled": true, "namespace": null, "tenant": null, "environment": null, "stage": null, "name": null, "delimiter": null,
+				"attributes": [], "tags": {}, "additional_tag_map": {}, "regex_replace_chars": null, "label_order": [], "id_length_limit": null,
+				"label_key_case": null, "label_value_case": null, "descriptor_formats": {}, "labels_as_tags": ["unset"]}`,
+		},
+		{name: "unknown keys are ignored", data: `{"namespace": "eg", "future_setting": 1}`},
+		{name: "invalid JSON", data: `{"namespace": `, wantErr: "decoding context"},
+		{name: "unknown label", data: `{"label_order": ["name", "workspace"]}`, wantErr: `label_order: "workspace"`},
+		{name: "invalid key case", data: `{"label_key_case": "none"}`, wantErr: "label_key_case"},
+		{name: "short id_length_limit", data: `{"id_length_limit": 4}`, wantErr: "id_length_limit"},
+		{name: "invalid regex", data: `{"regex_replace_chars": "/[/"}`, wantErr: "regex_replace_chars"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := ParseNullLabelContext(tt.data)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if settings := c.Settings(); len(settings) > 0 {
+				t.Errorf("Settings() = %v, want none", settings)
+			}
+		})
+	}
+}
+
+func TestNullLabelContext_RoundTrip(t *testing.T) {
+	in := NullLabel{
+		Namespace:  "CompanyName",
+		Tenant:     "H.R.H",
+		Stage:      "build",
+		Name:       "Winston Churchroom",
+		Attributes: []string{"fire", "water"},
+		Delimiter:  "-",
+		LabelOrder: []string{"name", "tenant", "stage", "attributes"},
+		Tags:       map[string]string{"City": "Dublin"},
+		NullLabelOptions: NullLabelOptions{
+			IDLengthLimit: 24,
+			LabelKeyCase:  CaseUpper,
+		},
+	}
+	out := in.Generate()
+
+	data, err := json.Marshal(in.Context(out))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := ParseNullLabelContext(string(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A null-label module that only gets the context reproduces the label.
+	var next NullLabel
+	next.Namespace, next.Tenant, next.Stage, next.Name = *c.Namespace, *c.Tenant, *c.Stage, *c.Name
+	next.Delimiter, next.Attributes, next.Tags = *c.Delimiter, c.Attributes, c.Tags
+	c.Apply(&next)
+	got := next.Generate()
+
+	if got.ID != out.ID || !maps.Equal(got.Tags, out.Tags) {
+		t.Errorf("round trip = %q %v, want %q %v", got.ID, got.Tags, out.ID, out.Tags)
+	}
+}
+
+func TestConventionContext(t *testing.T) {
+	cfg := &LabelConfig{
+		Tenant:      "dpl",
+		Environment: "ane2",
+		Stage:       "dev",
+		Workspace:   "sales-api",
+		Delimiter:   "-",
+	}
+	c := ConventionContext(cfg, "emr", "", "", map[string]string{"Name": "x"})
+
+	if want := []string{"tenant", "environment", "stage", "name", "attributes"}; !slices.Equal(c.LabelOrder, want) {
+		t.Errorf("label_order = %v, want %v", c.LabelOrder, want)
+	}
+	if *c.Name != "emr" || *c.Delimiter != "-" || c.Attributes == nil || len(c.Attributes) != 0 {
+		t.Errorf("unexpected context: name %q, delimiter %q, attributes %#v", *c.Name, *c.Delimiter, c.Attributes)
+	}
+}
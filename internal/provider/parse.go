@@ -0,0 +1,234 @@
+package provider
+
+import (
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// NameMatch holds the values recovered from a name by ParseName.
+type NameMatch struct {
+	Qualifier   string
+	InstanceKey string
+	// Components maps each component present in the name to its value,
+	// e.g. {"tenant": "dpl", "resource_type": "sg", "workspace": "sales-api"}.
+	// With CompatNullLabel the keys are null-label labels.
+	Components map[string]string
+	// Overrides maps the provider-level components whose value in the name
+	// differs from the configured one to that value, e.g. {"stage": "shared"}.
+	Overrides map[string]string
+}
+
+// ParseOptions relaxes ParseNameWith for names generated with per-read
+// overrides of provider-level components, as the label data source allows
+// through its arguments or context_json.
+type ParseOptions struct {
+	// Overrides are values a provider-level component may take in place of
+	// its configured value, keyed by component, e.g. the Stage tag of the
+	// resource.
+	Overrides map[string][]string
+	// AnyOverride lets every provider-level component take any value, or
+	// none, for names whose overrides cannot be known, e.g. of resources
+	// without tags.
+	AnyOverride bool
+}
+
+// ParseName reports whether name is an ID the convention generates for
+// resourceType with some qualifier and instance_key, and recovers them.
+// Provider-level components must match their current values.
+// e.g. "dpl-ane2-sg-dev-emr-sales-api" → {Qualifier: "emr", ...}
+func ParseName(cfg *LabelConfig, resourceType string, name string, delimiter string) (NameMatch, bool) {
+	if delimiter == "" {
+		delimiter = cfg.Delimiter
+	}
+	return parseName(cfg, resourceType, name, delimiter, nil, false)
+}
+
+// ParseNameWith is ParseName that also accepts names generated with
+// overrides: when the configured values do not match, provider-level
+// components may take their values from opts, and the workspace any value
+// or none. The recovered values are returned in Overrides. A segment that
+// may be the qualifier or an overridden workspace is taken as the qualifier.
+// e.g. "dpl-ane2-kms-shared" with opts.Overrides["stage"] = ["shared"] →
+// {Overrides: {"stage": "shared", "workspace": ""}}
+func ParseNameWith(cfg *LabelConfig, resourceType string, name string, delimiter string, opts ParseOptions) (NameMatch, bool) {
+	if delimiter == "" {
+		delimiter = cfg.Delimiter
+	}
+	// The configured workspace is tried before any other, so that it is not
+	// split into a qualifier and an overridden workspace.
+	if match, ok := parseName(cfg, resourceType, name, delimiter, nil, false); ok {
+		return match, true
+	}
+	if match, ok := parseName(cfg, resourceType, name, delimiter, &opts, false); ok {
+		return match, true
+	}
+	return parseName(cfg, resourceType, name, delimiter, &opts, true)
+}
+
+func parseName(cfg *LabelConfig, resourceType string, name string, delimiter string, opts *ParseOptions, anyWorkspace bool) (NameMatch, bool) {
+	re, components := nameRegexp(cfg, resourceType, delimiter, opts, anyWorkspace)
+	// Every segment is matched with a leading delimiter, so that optional
+	// segments at the start need no special case.
+	m := re.FindStringSubmatch(delimiter + name)
+	if m == nil {
+		return NameMatch{}, false
+	}
+
+	match := NameMatch{Components: map[string]string{}}
+	for i, group := range re.SubexpNames() {
+		switch group {
+		case "":
+		case "qualifier":
+			match.Qualifier = m[i]
+		case "instance_key":
+			match.InstanceKey = m[i]
+		default:
+			n, _ := strconv.Atoi(strings.TrimPrefix(group, "c"))
+			component := components[n]
+			value := m[i]
+			if cfg.EscapeDelimiter {
+				value = strings.ReplaceAll(value, delimiter+delimiter, delimiter)
+			}
+			if value != configuredValue(cfg, component, delimiter) {
+				if match.Overrides == nil {
+					match.Overrides = map[string]string{}
+				}
+				match.Overrides[component] = value
+			}
+		}
+	}
+
+	resolved := cfg
+	if match.Overrides != nil {
+		resolved = cfg.WithOverrides(match.Overrides)
+	}
+	if cfg.Compat == CompatNullLabel {
+		labels := nullLabelValues(resolved, delimiter)
+		labels["name"], labels["attributes"] = match.Qualifier, match.InstanceKey
+		for _, label := range resolved.nullLabelOrder() {
+			if v := labels[label]; v != "" {
+				match.Components[label] = v
+			}
+		}
+		return match, true
+	}
+	in := labelInputs{resourceType: resourceType, qualifier: match.Qualifier, instanceKey: match.InstanceKey}
+	for _, name := range resolved.labelOrder() {
+		if segs := resolved.segments(name, in); len(segs) > 0 {
+			match.Components[name] = strings.Join(segs, delimiter)
+		}
+	}
+	return match, true
+}
+
+// nameRegexp returns the pattern of the names the convention generates for
+// resourceType. Without opts provider-level components match their
+// configured value; with opts they are captured in groups c0, c1, ... for
+// the components at that index of the returned slice. The workspace keeps
+// its configured value unless anyWorkspace is set.
+func nameRegexp(cfg *LabelConfig, resourceType string, delimiter string, opts *ParseOptions, anyWorkspace bool) (*regexp.Regexp, []string) {
+	d := regexp.QuoteMeta(delimiter)
+	literal := func(value string) string {
+		if cfg.EscapeDelimiter {
+			value = EscapeSegment(value, delimiter)
+		}
+		return regexp.QuoteMeta(value)
+	}
+
+	order := cfg.labelOrder()
+	qualifier, instanceKey := "qualifier", "instance_key"
+	if cfg.Compat == CompatNullLabel {
+		order = cfg.nullLabelOrder()
+		qualifier, instanceKey = "name", "attributes"
+	}
+
+	var components []string
+	var b strings.Builder
+	b.WriteString("^")
+	for _, name := range order {
+		switch {
+		case name == qualifier:
+			b.WriteString("(?:" + d + "(?P<qualifier>.+?))?")
+		case name == instanceKey:
+			b.WriteString("(?:" + d + "(?P<instance_key>.+?))?")
+		case name == "resource_type":
+			if resourceType != "" {
+				b.WriteString(d + literal(resourceType))
+			}
+		case opts == nil || (name == "workspace" && !anyWorkspace):
+			if value := configuredValue(cfg, name, delimiter); value != "" {
+				b.WriteString(d + literal(value))
+			}
+		case opts.AnyOverride || (name == "workspace" && cfg.Compat != CompatNullLabel):
+			group := "c" + strconv.Itoa(len(components))
+			components = append(components, name)
+			b.WriteString("(?:" + d + "(?P<" + group + ">.+?))?")
+		default:
+			var alternatives []string
+			for _, value := range append([]string{configuredValue(cfg, name, delimiter)}, opts.Overrides[name]...) {
+				if value != "" && !slices.Contains(alternatives, literal(value)) {
+					alternatives = append(alternatives, literal(value))
+				}
+			}
+			if len(alternatives) == 0 {
+				continue
+			}
+			group := "c" + strconv.Itoa(len(components))
+			components = append(components, name)
+			b.WriteString(d + "(?P<" + group + ">" + strings.Join(alternatives, "|") + ")")
+		}
+	}
+	b.WriteString("$")
+	return regexp.MustCompile(b.String()), components
+}
+
+// configuredValue returns the value a provider-level component contributes
+// to names, e.g. the abbreviated workspace segments joined by delimiter.
+func configuredValue(cfg *LabelConfig, name string, delimiter string) string {
+	if cfg.Compat == CompatNullLabel {
+		return nullLabelValues(cfg, delimiter)[name]
+	}
+	return strings.Join(cfg.segments(name, labelInputs{}), delimiter)
+}
+
+// nullLabelValues returns the formatted provider-level labels of a
+// null-label ID.
+func nullLabelValues(cfg *LabelConfig, delimiter string) map[string]string {
+	out := NullLabelFor(cfg, "", nil, delimiter).Generate()
+	return map[string]string{
+		"namespace":   out.Namespace,
+		"tenant":      out.Tenant,
+		"environment": out.Environment,
+		"stage":       out.Stage,
+	}
+}
+
+// nullLabelOrder returns the label order of null-label IDs.
+func (c *LabelConfig) nullLabelOrder() []string {
+	if len(c.LabelOrder) == 0 {
+		return NullLabelOrder
+	}
+	return c.LabelOrder
+}
+
+// TagKey returns the tag key a label carries, e.g. "Name" for name,
+// "Stage" for stage or "CostCenter" for cost_center. With CompatNullLabel
+// it follows label_key_case.
+func (c *LabelConfig) TagKey(label string) string {
+	if c.Compat == CompatNullLabel {
+		keyCase := c.NullLabel.LabelKeyCase
+		if keyCase == "" {
+			keyCase = DefaultNullLabelKeyCase
+		}
+		return nullLabelTagKey(label, keyCase)
+	}
+	switch label {
+	case "name":
+		return "Name"
+	case "attributes":
+		return "Attributes"
+	}
+	return ComponentTagKey(label)
+}
@@ -0,0 +1,279 @@
+package provider
+
+import (
+	"maps"
+	"testing"
+)
+
+func TestParseName(t *testing.T) {
+	cfg := &LabelConfig{Tenant: "dpl", Environment: "ane2", Stage: "dev", Workspace: "sales-api", Delimiter: "-"}
+
+	tests := []struct {
+		name         string
+		cfg          *LabelConfig
+		resourceType string
+		id           string
+		delimiter    string
+		wantOK       bool
+		want         NameMatch
+	}{
+		{
+			name:         "no qualifier",
+			resourceType: "sg",
+			id:           "dpl-ane2-sg-dev-sales-api",
+			wantOK:       true,
+		},
+		{
+			name:         "qualifier",
+			resourceType: "sg",
+			id:           "dpl-ane2-sg-dev-emr-sales-api",
+			wantOK:       true,
+			want:         NameMatch{Qualifier: "emr"},
+		},
+		{
+			name:         "qualifier and instance key",
+			resourceType: "role",
+			id:           "dpl-ane2-role-dev-emr-sales-api-shared-pii-etl",
+			wantOK:       true,
+			want:         NameMatch{Qualifier: "emr", InstanceKey: "shared-pii-etl"},
+		},
+		{
+			name:         "delimiter override",
+			resourceType: "db",
+			id:           "dpl_ane2_db_dev_refined_sales_api",
+			delimiter:    "_",
+			wantOK:       true,
+			want:         NameMatch{Qualifier: "refined"},
+		},
+		{
+			name:         "other resource type",
+			resourceType: "sg",
+			id:           "dpl-ane2-role-dev-sales-api",
+		},
+		{
+			name:         "other stage",
+			resourceType: "sg",
+			id:           "dpl-ane2-sg-prd-sales-api",
+		},
+		{
+			name:         "hand-written name",
+			resourceType: "sg",
+			id:           "web-sg",
+		},
+		{
+			name:         "custom label order",
+			cfg:          &LabelConfig{Tenant: "dpl", Stage: "dev", Delimiter: "-", LabelOrder: []string{"resource_type", "tenant", "qualifier", "stage"}},
+			resourceType: "s3",
+			id:           "s3-dpl-logs-dev",
+			wantOK:       true,
+			want:         NameMatch{Qualifier: "logs"},
+		},
+		{
+			name:         "escaped delimiter",
+			cfg:          &LabelConfig{Tenant: "dpl", Environment: "ane2", Stage: "dev", Workspace: "sales-api", Delimiter: "-", EscapeDelimiter: true},
+			resourceType: "sg",
+			id:           "dpl-ane2-sg-dev-sales--api",
+			wantOK:       true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := tt.cfg
+			if c == nil {
+				c = cfg
+			}
+			got, ok := ParseName(c, tt.resourceType, tt.id, tt.delimiter)
+			if ok != tt.wantOK {
+				t.Fatalf("ParseName(%q) ok = %v, want %v", tt.id, ok, tt.wantOK)
+			}
+			if got.Qualifier != tt.want.Qualifier || got.InstanceKey != tt.want.InstanceKey {
+				t.Errorf("ParseName(%q) = %+v, want %+v", tt.id, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseName_Components(t *testing.T) {
+	cfg := &LabelConfig{Tenant: "dpl", Environment: "ane2", Stage: "dev", Workspace: "sales-api", Delimiter: "-"}
+
+	got, ok := ParseName(cfg, "role", "dpl-ane2-role-dev-emr-sales-api-etl", "")
+	if !ok {
+		t.Fatal("ParseName did not match")
+	}
+	want := map[string]string{
+		"tenant":        "dpl",
+		"environment":   "ane2",
+		"resource_type": "role",
+		"stage":         "dev",
+		"qualifier":     "emr",
+		"workspace":     "sales-api",
+		"instance_key":  "etl",
+	}
+	if !maps.Equal(got.Components, want) {
+		t.Errorf("Components = %v, want %v", got.Components, want)
+	}
+}
+
+func TestTargetName(t *testing.T) {
+	values := map[string]any{
+		"bucket": "dpl-ane2-s3-dev-logs",
+		"tags":   map[string]any{"Name": "dpl-ane2-vpc-dev"},
+		"count":  float64(3),
+	}
+
+	tests := []struct {
+		attribute string
+		want      string
+		wantOK    bool
+	}{
+		{"bucket", "dpl-ane2-s3-dev-logs", true},
+		{NameTag, "dpl-ane2-vpc-dev", true},
+		{"name", "", false},
+		{"count", "", false},
+	}
+
+	for _, tt := range tests {
+		got, ok := Target{NameAttribute: tt.attribute}.Name(values)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("Name(%s) = %q, %v, want %q, %v", tt.attribute, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestParseNameWith(t *testing.T) {
+	cfg := &LabelConfig{Tenant: "dpl", Environment: "ane2", Stage: "dev", Workspace: "sales-api", Delimiter: "-"}
+	compat := &LabelConfig{Compat: CompatNullLabel, Namespace: "EG", Stage: "prod", Delimiter: "-"}
+
+	tests := []struct {
+		name          string
+		cfg           *LabelConfig
+		resourceType  string
+		id            string
+		opts          ParseOptions
+		wantOK        bool
+		wantQualifier string
+		wantOverrides map[string]string
+	}{
+		{
+			name:         "configured values",
+			resourceType: "sg",
+			id:           "dpl-ane2-sg-dev-emr-sales-api",
+			opts:         ParseOptions{Overrides: map[string][]string{"stage": {"prd"}}},
+			wantOK:       true, wantQualifier: "emr",
+		},
+		{
+			name:          "stage override from tags",
+			resourceType:  "kms",
+			id:            "dpl-ane2-kms-shared-sales-api",
+			opts:          ParseOptions{Overrides: map[string][]string{"stage": {"shared"}}},
+			wantOK:        true,
+			wantOverrides: map[string]string{"stage": "shared"},
+		},
+		{
+			name:          "qualifier before an overridden workspace",
+			resourceType:  "kms",
+			id:            "dpl-ane2-kms-shared-core",
+			opts:          ParseOptions{Overrides: map[string][]string{"stage": {"shared"}}},
+			wantOK:        true,
+			wantQualifier: "core",
+			wantOverrides: map[string]string{"stage": "shared", "workspace": ""},
+		},
+		{
+			name:          "workspace dropped",
+			resourceType:  "kms",
+			id:            "dpl-ane2-kms-dev",
+			wantOK:        true,
+			wantOverrides: map[string]string{"workspace": ""},
+		},
+		{
+			name:         "stage not in the overrides",
+			resourceType: "kms",
+			id:           "dpl-ane2-kms-shared-sales-api",
+			opts:         ParseOptions{Overrides: map[string][]string{"stage": {"prd"}}},
+		},
+		{
+			name:          "any override",
+			resourceType:  "db",
+			id:            "acme-ane2-db-prd",
+			opts:          ParseOptions{AnyOverride: true},
+			wantOK:        true,
+			wantOverrides: map[string]string{"tenant": "acme", "stage": "prd", "workspace": ""},
+		},
+		{
+			name:         "other resource type",
+			resourceType: "sg",
+			id:           "dpl-ane2-db-dev",
+			opts:         ParseOptions{AnyOverride: true},
+		},
+		{
+			name:          "null-label",
+			cfg:           compat,
+			id:            "eg-prod-app-blue",
+			wantOK:        true,
+			wantQualifier: "app",
+		},
+		{
+			name:          "null-label override",
+			cfg:           compat,
+			id:            "eg-ue2-prod-app",
+			opts:          ParseOptions{Overrides: map[string][]string{"environment": {"ue2"}}},
+			wantOK:        true,
+			wantQualifier: "app",
+			wantOverrides: map[string]string{"environment": "ue2"},
+		},
+		{
+			name: "null-label other namespace",
+			cfg:  compat,
+			id:   "cp-prod-app",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := tt.cfg
+			if c == nil {
+				c = cfg
+			}
+			got, ok := ParseNameWith(c, tt.resourceType, tt.id, "", tt.opts)
+			if ok != tt.wantOK {
+				t.Fatalf("ParseNameWith(%q) ok = %v, want %v", tt.id, ok, tt.wantOK)
+			}
+			if got.Qualifier != tt.wantQualifier || !maps.Equal(got.Overrides, tt.wantOverrides) {
+				t.Errorf("ParseNameWith(%q) = %+v, want qualifier %q and overrides %v", tt.id, got, tt.wantQualifier, tt.wantOverrides)
+			}
+		})
+	}
+}
+
+func TestParseNameWith_NullLabelComponents(t *testing.T) {
+	cfg := &LabelConfig{Compat: CompatNullLabel, Namespace: "eg", Environment: "ue2", Stage: "prod", Delimiter: "-"}
+
+	got, ok := ParseNameWith(cfg, "ec2", "eg-ue2-prod-app-blue", "", ParseOptions{})
+	if !ok {
+		t.Fatal("ParseNameWith did not match")
+	}
+	want := map[string]string{"namespace": "eg", "environment": "ue2", "stage": "prod", "name": "app", "attributes": "blue"}
+	if !maps.Equal(got.Components, want) {
+		t.Errorf("Components = %v, want %v", got.Components, want)
+	}
+}
+
+func TestTagKey(t *testing.T) {
+	cfg := &LabelConfig{}
+	upper := &LabelConfig{Compat: CompatNullLabel, NullLabel: NullLabelOptions{LabelKeyCase: CaseUpper}}
+	for _, tt := range []struct {
+		cfg   *LabelConfig
+		label string
+		want  string
+	}{
+		{cfg, "name", "Name"},
+		{cfg, "cost_center", "CostCenter"},
+		{&LabelConfig{Compat: CompatNullLabel}, "stage", "Stage"},
+		{upper, "attributes", "ATTRIBUTES"},
+	} {
+		if got := tt.cfg.TagKey(tt.label); got != tt.want {
+			t.Errorf("TagKey(%q) = %q, want %q", tt.label, got, tt.want)
+		}
+	}
+}
@@ -3,15 +3,23 @@ package provider
 import (
 	"context"
 	"os"
+	"regexp"
 
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
-var _ provider.Provider = (*LabelProvider)(nil)
+var (
+	_ provider.Provider              = (*LabelProvider)(nil)
+	_ provider.ProviderWithFunctions = (*LabelProvider)(nil)
+)
 
 type LabelProvider struct{}
 
@@ -22,8 +30,51 @@ type LabelProviderModel struct {
 	Workspace   types.String `tfsdk:"workspace"`
 	Namespace   types.String `tfsdk:"namespace"`
 	Delimiter   types.String `tfsdk:"delimiter"`
+
+	Region      types.String `tfsdk:"region"`
+	RegionStyle types.String `tfsdk:"region_style"`
+	RegionCodes types.Map    `tfsdk:"region_codes"`
+
+	Components         types.Map  `tfsdk:"components"`
+	LabelOrder         types.List `tfsdk:"label_order"`
+	RequiredComponents types.List `tfsdk:"required_components"`
+
+	WorkspaceDelimiter     types.String `tfsdk:"workspace_delimiter"`
+	WorkspaceOpaque        types.Bool   `tfsdk:"workspace_opaque"`
+	WorkspaceAbbreviations types.Map    `tfsdk:"workspace_abbreviations"`
+
+	WorkspaceFromPath  types.String `tfsdk:"workspace_from_path"`
+	WorkspaceDetectors types.List   `tfsdk:"workspace_detectors"`
+	WorkspacePattern   types.String `tfsdk:"workspace_pattern"`
+
+	AttributesDelimiter  types.String `tfsdk:"attributes_delimiter"`
+	AttributesComponents types.List   `tfsdk:"attributes_components"`
+	AttributesTag        types.Bool   `tfsdk:"attributes_tag"`
+
+	AmbiguityCheck  types.String `tfsdk:"ambiguity_check"`
+	EscapeDelimiter types.Bool   `tfsdk:"escape_delimiter"`
+	DuplicateCheck  types.String `tfsdk:"duplicate_check"`
+	SuffixSalt      types.String `tfsdk:"suffix_salt"`
+
+	LegacyNames types.Map `tfsdk:"legacy_names"`
+
+	ConventionFile types.String `tfsdk:"convention_file"`
+
+	Aliases      types.Map  `tfsdk:"aliases"`
+	RawValueTags types.Bool `tfsdk:"raw_value_tags"`
+
+	Compat            types.String `tfsdk:"compat"`
+	IDLengthLimit     types.Int64  `tfsdk:"id_length_limit"`
+	RegexReplaceChars types.String `tfsdk:"regex_replace_chars"`
+	LabelKeyCase      types.String `tfsdk:"label_key_case"`
+	LabelValueCase    types.String `tfsdk:"label_value_case"`
+	LabelsAsTags      types.List   `tfsdk:"labels_as_tags"`
 }
 
+// componentNamePattern restricts custom component names so they map cleanly
+// to LABEL_COMPONENT_<NAME> env vars and tag keys.
+var componentNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
 func New() provider.Provider {
 	return &LabelProvider{}
 }
@@ -60,6 +111,132 @@ func (p *LabelProvider) Schema(_ context.Context, _ provider.SchemaRequest, resp
 				Optional:    true,
 				Description: "Default delimiter (default: -). Falls back to LABEL_DELIMITER env var.",
 			},
+			"region": schema.StringAttribute{
+				Optional:    true,
+				Description: "Cloud region name (e.g. ap-northeast-2). When environment is not set, it is derived from this region's abbreviation. Falls back to LABEL_REGION env var.",
+			},
+			"region_style": schema.StringAttribute{
+				Optional:    true,
+				Description: "Abbreviation style used to derive environment from region: short (e.g. ane2) or fixed (e.g. an2). Default: short. Falls back to LABEL_REGION_STYLE env var.",
+			},
+			"region_codes": schema.MapAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				Description: "Custom region abbreviations keyed by region name. Extends or overrides the built-in region table.",
+			},
+			"components": schema.MapAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				Description: "Custom naming components (e.g. account, region, cost_center) emitted as tags and available in label_order. Each falls back to a LABEL_COMPONENT_<NAME> env var.",
+			},
+			"label_order": schema.ListAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				Description: "Order of components in generated IDs (default: tenant, environment, resource_type, stage, qualifier, workspace, instance_key). Falls back to the comma-separated LABEL_ORDER env var.",
+			},
+			"required_components": schema.ListAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				Description: "Components that must have a value before an ID is generated (default: tenant, environment, stage). Falls back to the comma-separated LABEL_REQUIRED_COMPONENTS env var.",
+			},
+			"workspace_delimiter": schema.StringAttribute{
+				Optional:    true,
+				Description: "Characters the workspace is split on (default: -). Each character is a separator, e.g. \"-.\" splits on both - and .. Falls back to LABEL_WORKSPACE_DELIMITER env var.",
+			},
+			"workspace_opaque": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Keep the workspace as a single segment instead of splitting it. Default: false. Falls back to LABEL_WORKSPACE_OPAQUE env var.",
+			},
+			"workspace_abbreviations": schema.MapAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				Description: "Replacement values for individual workspace segments (e.g. { analytics = \"anl\" }).",
+			},
+			"workspace_from_path": schema.StringAttribute{
+				Optional:    true,
+				Description: "Derive the workspace from the Terraform working directory when it is not set. Glob pattern matched against trailing directories, where * captures one directory, ** one or more, and {name} a named component (e.g. stacks/**/{stage}); or regex:<expr> with capture groups. Falls back to LABEL_WORKSPACE_FROM_PATH env var.",
+			},
+			"workspace_detectors": schema.ListAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				Description: "Ordered CI/CD detectors used to derive the workspace when it is not set: tfc, scalr, spacelift, env0, atlantis, terraform, env:<VAR>, or default for all built-ins. Falls back to the comma-separated LABEL_WORKSPACE_DETECTORS env var.",
+			},
+			"workspace_pattern": schema.StringAttribute{
+				Optional:    true,
+				Description: "Regular expression with named groups (e.g. ^(?P<workspace>.+)-(?P<stage>dev|prd)$) applied to the detected value to extract components. Falls back to LABEL_WORKSPACE_PATTERN env var.",
+			},
+			"attributes_delimiter": schema.StringAttribute{
+				Optional:    true,
+				Description: "Delimiter used to join the Attributes tag (default: -). Falls back to LABEL_ATTRIBUTES_DELIMITER env var.",
+			},
+			"attributes_components": schema.ListAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				Description: "Components that make up the Attributes tag, in order (default: qualifier, workspace, instance_key). Falls back to the comma-separated LABEL_ATTRIBUTES_COMPONENTS env var.",
+			},
+			"attributes_tag": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Emit the Attributes tag (default: true). Falls back to LABEL_ATTRIBUTES_TAG env var.",
+			},
+			"ambiguity_check": schema.StringAttribute{
+				Optional:    true,
+				Description: "Check generated IDs for components that contain the delimiter: off, warn or error. Default: off. Falls back to LABEL_AMBIGUITY_CHECK env var.",
+			},
+			"escape_delimiter": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Render each component as one ID segment and double any delimiter inside it, so IDs can be split back into components. Default: false. Falls back to LABEL_ESCAPE_DELIMITER env var.",
+			},
+			"duplicate_check": schema.StringAttribute{
+				Optional:    true,
+				Description: "Report IDs generated by more than one label data source in the same run: off, warn or error. Default: warn. Falls back to LABEL_DUPLICATE_CHECK env var.",
+			},
+			"suffix_salt": schema.StringAttribute{
+				Optional:    true,
+				Description: "Salt added to the seed of every unique suffix, so that different organizations get different suffixes for the same names. Falls back to LABEL_SUFFIX_SALT env var.",
+			},
+			"legacy_names": schema.MapAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				Description: "Names that predate the convention, keyed by the computed ID or by a data source legacy_key. The label data source returns the legacy name as id; tags still follow the convention.",
+			},
+			"convention_file": schema.StringAttribute{
+				Optional:    true,
+				Description: "Path to a JSON convention file whose keys are provider attributes. Values set in the provider block or LABEL_* env vars take precedence. Falls back to LABEL_CONVENTION_FILE env var.",
+			},
+			"aliases": schema.MapAttribute{
+				Optional:    true,
+				ElementType: types.MapType{ElemType: types.StringType},
+				Description: "Per-component maps of alternative values to their canonical value (e.g. { stage = { production = \"prd\" } }). Applied before naming; matching is case-insensitive.",
+			},
+			"raw_value_tags": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Add a <Key>Raw tag (e.g. StageRaw) holding the original value of each component normalized by aliases. Default: false.",
+			},
+			"compat": schema.StringAttribute{
+				Optional:    true,
+				Description: "Naming algorithm: none, or null-label to reproduce the id and tags of cloudposse/terraform-null-label with qualifier as its name and instance_key as its attribute. Default: none. Falls back to LABEL_COMPAT env var.",
+			},
+			"id_length_limit": schema.Int64Attribute{
+				Optional:    true,
+				Description: "null-label id_length_limit: longer IDs are truncated and end in a hash. 0 is unlimited, else at least 6. Requires compat = \"null-label\". Falls back to LABEL_ID_LENGTH_LIMIT env var.",
+			},
+			"regex_replace_chars": schema.StringAttribute{
+				Optional:    true,
+				Description: "null-label regex_replace_chars: characters removed from every label, a regular expression when wrapped in slashes (default: /[^-a-zA-Z0-9]/). Requires compat = \"null-label\". Falls back to LABEL_REGEX_REPLACE_CHARS env var.",
+			},
+			"label_key_case": schema.StringAttribute{
+				Optional:    true,
+				Description: "null-label label_key_case: case of tag keys, lower, title or upper (default: title). Requires compat = \"null-label\". Falls back to LABEL_KEY_CASE env var.",
+			},
+			"label_value_case": schema.StringAttribute{
+				Optional:    true,
+				Description: "null-label label_value_case: case of label values, lower, title, upper or none (default: lower). Requires compat = \"null-label\". Falls back to LABEL_VALUE_CASE env var.",
+			},
+			"labels_as_tags": schema.ListAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				Description: "null-label labels_as_tags: labels emitted as tags (default: all). Requires compat = \"null-label\". Falls back to the comma-separated LABEL_LABELS_AS_TAGS env var.",
+			},
 		},
 	}
 }
@@ -71,31 +248,150 @@ func (p *LabelProvider) Configure(ctx context.Context, req provider.ConfigureReq
 		return
 	}
 
-	delimiter := stringValueOrEnv(model.Delimiter, "LABEL_DELIMITER")
-	if delimiter == "" {
-		delimiter = "-"
+	settings, diags := model.settings(ctx)
+	resp.Diagnostics.Append(diags...)
+
+	var file Settings
+	var fileSource string
+	if filePath := stringValueOrEnv(model.ConventionFile, ConventionFileEnvVar); filePath != "" {
+		convention, err := LoadConventionFile(filePath)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("convention_file"), "Invalid Convention File", err.Error())
+		} else {
+			file = convention.Settings
+			fileSource = convention.Source()
+		}
+	}
+	if resp.Diagnostics.HasError() {
+		return
 	}
 
-	cfg := &LabelConfig{
-		Tenant:      stringValueOrEnv(model.Tenant, "LABEL_TENANT"),
-		Environment: stringValueOrEnv(model.Environment, "LABEL_ENVIRONMENT"),
-		Stage:       stringValueOrEnv(model.Stage, "LABEL_STAGE"),
-		Workspace:   stringValueOrEnv(model.Workspace, "LABEL_WORKSPACE"),
-		Namespace:   stringValueOrEnv(model.Namespace, "LABEL_NAMESPACE"),
-		Delimiter:   delimiter,
+	cfg, errs := ResolveConfig(settings, file, fileSource, Environ(os.Environ()), os.Getwd)
+	for _, e := range errs {
+		if e.Attribute == "" {
+			resp.Diagnostics.AddError(e.Summary, e.Detail)
+		} else {
+			resp.Diagnostics.AddAttributeError(path.Root(e.Attribute), e.Summary, e.Detail)
+		}
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	for name, source := range cfg.Sources {
+		tflog.Debug(ctx, "Resolved label component", map[string]interface{}{
+			"component": name,
+			"source":    source,
+		})
 	}
 
 	resp.DataSourceData = cfg
+	resp.ResourceData = cfg
 }
 
 func (p *LabelProvider) Resources(_ context.Context) []func() resource.Resource {
-	return nil
+	return []func() resource.Resource{
+		NewNameResource,
+		NewRandomResource,
+	}
 }
 
 func (p *LabelProvider) DataSources(_ context.Context) []func() datasource.DataSource {
 	return []func() datasource.DataSource{
 		NewLabelDataSource,
+		NewRegionDataSource,
+	}
+}
+
+func (p *LabelProvider) Functions(_ context.Context) []func() function.Function {
+	return []func() function.Function{
+		NewRegionCodeFunction,
+		NewRegionNameFunction,
+	}
+}
+
+// settings converts the provider block to Settings. Null and unknown
+// values are unset.
+func (m LabelProviderModel) settings(ctx context.Context) (Settings, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	list := func(v types.List) []string {
+		if v.IsNull() || v.IsUnknown() {
+			return nil
+		}
+		out := []string{}
+		diags.Append(v.ElementsAs(ctx, &out, false)...)
+		return out
+	}
+	stringMap := func(v types.Map) map[string]string {
+		if v.IsNull() || v.IsUnknown() {
+			return nil
+		}
+		out := map[string]string{}
+		diags.Append(v.ElementsAs(ctx, &out, false)...)
+		return out
+	}
+
+	s := Settings{
+		Tenant:                 stringPointer(m.Tenant),
+		Environment:            stringPointer(m.Environment),
+		Stage:                  stringPointer(m.Stage),
+		Workspace:              stringPointer(m.Workspace),
+		Namespace:              stringPointer(m.Namespace),
+		Delimiter:              stringPointer(m.Delimiter),
+		Region:                 stringPointer(m.Region),
+		RegionStyle:            stringPointer(m.RegionStyle),
+		RegionCodes:            stringMap(m.RegionCodes),
+		Components:             stringMap(m.Components),
+		LabelOrder:             list(m.LabelOrder),
+		RequiredComponents:     list(m.RequiredComponents),
+		WorkspaceDelimiter:     stringPointer(m.WorkspaceDelimiter),
+		WorkspaceOpaque:        boolPointer(m.WorkspaceOpaque),
+		WorkspaceAbbreviations: stringMap(m.WorkspaceAbbreviations),
+		WorkspaceFromPath:      stringPointer(m.WorkspaceFromPath),
+		WorkspaceDetectors:     list(m.WorkspaceDetectors),
+		WorkspacePattern:       stringPointer(m.WorkspacePattern),
+		AttributesDelimiter:    stringPointer(m.AttributesDelimiter),
+		AttributesComponents:   list(m.AttributesComponents),
+		AttributesTag:          boolPointer(m.AttributesTag),
+		AmbiguityCheck:         stringPointer(m.AmbiguityCheck),
+		EscapeDelimiter:        boolPointer(m.EscapeDelimiter),
+		DuplicateCheck:         stringPointer(m.DuplicateCheck),
+		SuffixSalt:             stringPointer(m.SuffixSalt),
+		LegacyNames:            stringMap(m.LegacyNames),
+		RawValueTags:           boolPointer(m.RawValueTags),
+		Compat:                 stringPointer(m.Compat),
+		IDLengthLimit:          int64Pointer(m.IDLengthLimit),
+		RegexReplaceChars:      stringPointer(m.RegexReplaceChars),
+		LabelKeyCase:           stringPointer(m.LabelKeyCase),
+		LabelValueCase:         stringPointer(m.LabelValueCase),
+		LabelsAsTags:           list(m.LabelsAsTags),
+	}
+	if !m.Aliases.IsNull() && !m.Aliases.IsUnknown() {
+		s.Aliases = map[string]map[string]string{}
+		diags.Append(m.Aliases.ElementsAs(ctx, &s.Aliases, false)...)
+	}
+	return s, diags
+}
+
+func stringPointer(v types.String) *string {
+	if v.IsNull() || v.IsUnknown() {
+		return nil
+	}
+	return v.ValueStringPointer()
+}
+
+func boolPointer(v types.Bool) *bool {
+	if v.IsNull() || v.IsUnknown() {
+		return nil
+	}
+	return v.ValueBoolPointer()
+}
+
+func int64Pointer(v types.Int64) *int64 {
+	if v.IsNull() || v.IsUnknown() {
+		return nil
 	}
+	return v.ValueInt64Pointer()
 }
 
 func stringValueOrEnv(v types.String, envKey string) string {
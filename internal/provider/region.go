@@ -0,0 +1,262 @@
+package provider
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Region code styles.
+const (
+	RegionStyleShort = "short" // variable length, e.g. ap-northeast-2 → ane2
+	RegionStyleFixed = "fixed" // always three characters, e.g. ap-northeast-2 → an2
+)
+
+// RegionCode holds the abbreviations of a single cloud region.
+type RegionCode struct {
+	Cloud  string
+	Region string
+	Short  string
+	Fixed  string
+}
+
+// Code returns the abbreviation for the given style.
+func (r RegionCode) Code(style string) string {
+	if style == RegionStyleFixed {
+		return r.Fixed
+	}
+	return r.Short
+}
+
+// builtinRegionCodes is the default abbreviation table. Codes are unique per
+// cloud and style.
+var builtinRegionCodes = []RegionCode{
+	// AWS
+	{Cloud: "aws", Region: "us-east-1", Short: "ue1", Fixed: "ue1"},
+	{Cloud: "aws", Region: "us-east-2", Short: "ue2", Fixed: "ue2"},
+	{Cloud: "aws", Region: "us-west-1", Short: "uw1", Fixed: "uw1"},
+	{Cloud: "aws", Region: "us-west-2", Short: "uw2", Fixed: "uw2"},
+	{Cloud: "aws", Region: "us-gov-east-1", Short: "uge1", Fixed: "ge1"},
+	{Cloud: "aws", Region: "us-gov-west-1", Short: "ugw1", Fixed: "gw1"},
+	{Cloud: "aws", Region: "ca-central-1", Short: "cc1", Fixed: "cc1"},
+	{Cloud: "aws", Region: "ca-west-1", Short: "cw1", Fixed: "cw1"},
+	{Cloud: "aws", Region: "mx-central-1", Short: "xc1", Fixed: "xc1"},
+	{Cloud: "aws", Region: "sa-east-1", Short: "se1", Fixed: "se1"},
+	{Cloud: "aws", Region: "eu-central-1", Short: "ec1", Fixed: "ec1"},
+	{Cloud: "aws", Region: "eu-central-2", Short: "ec2", Fixed: "ec2"},
+	{Cloud: "aws", Region: "eu-west-1", Short: "ew1", Fixed: "ew1"},
+	{Cloud: "aws", Region: "eu-west-2", Short: "ew2", Fixed: "ew2"},
+	{Cloud: "aws", Region: "eu-west-3", Short: "ew3", Fixed: "ew3"},
+	{Cloud: "aws", Region: "eu-north-1", Short: "en1", Fixed: "en1"},
+	{Cloud: "aws", Region: "eu-south-1", Short: "es1", Fixed: "es1"},
+	{Cloud: "aws", Region: "eu-south-2", Short: "es2", Fixed: "es2"},
+	{Cloud: "aws", Region: "ap-east-1", Short: "ae1", Fixed: "ae1"},
+	{Cloud: "aws", Region: "ap-east-2", Short: "ae2", Fixed: "ae2"},
+	{Cloud: "aws", Region: "ap-northeast-1", Short: "ane1", Fixed: "an1"},
+	{Cloud: "aws", Region: "ap-northeast-2", Short: "ane2", Fixed: "an2"},
+	{Cloud: "aws", Region: "ap-northeast-3", Short: "ane3", Fixed: "an3"},
+	{Cloud: "aws", Region: "ap-southeast-1", Short: "ase1", Fixed: "as1"},
+	{Cloud: "aws", Region: "ap-southeast-2", Short: "ase2", Fixed: "as2"},
+	{Cloud: "aws", Region: "ap-southeast-3", Short: "ase3", Fixed: "as3"},
+	{Cloud: "aws", Region: "ap-southeast-4", Short: "ase4", Fixed: "as4"},
+	{Cloud: "aws", Region: "ap-southeast-5", Short: "ase5", Fixed: "as5"},
+	{Cloud: "aws", Region: "ap-southeast-7", Short: "ase7", Fixed: "as7"},
+	{Cloud: "aws", Region: "ap-south-1", Short: "aso1", Fixed: "ai1"},
+	{Cloud: "aws", Region: "ap-south-2", Short: "aso2", Fixed: "ai2"},
+	{Cloud: "aws", Region: "me-central-1", Short: "mc1", Fixed: "mc1"},
+	{Cloud: "aws", Region: "me-south-1", Short: "ms1", Fixed: "ms1"},
+	{Cloud: "aws", Region: "il-central-1", Short: "ic1", Fixed: "ic1"},
+	{Cloud: "aws", Region: "af-south-1", Short: "fs1", Fixed: "fs1"},
+	// GCP
+	{Cloud: "gcp", Region: "us-central1", Short: "uc1", Fixed: "uc1"},
+	{Cloud: "gcp", Region: "us-east1", Short: "ue1", Fixed: "ue1"},
+	{Cloud: "gcp", Region: "us-east4", Short: "ue4", Fixed: "ue4"},
+	{Cloud: "gcp", Region: "us-east5", Short: "ue5", Fixed: "ue5"},
+	{Cloud: "gcp", Region: "us-south1", Short: "us1", Fixed: "us1"},
+	{Cloud: "gcp", Region: "us-west1", Short: "uw1", Fixed: "uw1"},
+	{Cloud: "gcp", Region: "us-west2", Short: "uw2", Fixed: "uw2"},
+	{Cloud: "gcp", Region: "us-west3", Short: "uw3", Fixed: "uw3"},
+	{Cloud: "gcp", Region: "us-west4", Short: "uw4", Fixed: "uw4"},
+	{Cloud: "gcp", Region: "northamerica-northeast1", Short: "nne1", Fixed: "nn1"},
+	{Cloud: "gcp", Region: "northamerica-northeast2", Short: "nne2", Fixed: "nn2"},
+	{Cloud: "gcp", Region: "northamerica-south1", Short: "ns1", Fixed: "ns1"},
+	{Cloud: "gcp", Region: "southamerica-east1", Short: "se1", Fixed: "se1"},
+	{Cloud: "gcp", Region: "southamerica-west1", Short: "sw1", Fixed: "sw1"},
+	{Cloud: "gcp", Region: "europe-central2", Short: "ec2", Fixed: "ec2"},
+	{Cloud: "gcp", Region: "europe-north1", Short: "en1", Fixed: "en1"},
+	{Cloud: "gcp", Region: "europe-north2", Short: "en2", Fixed: "en2"},
+	{Cloud: "gcp", Region: "europe-southwest1", Short: "esw1", Fixed: "es1"},
+	{Cloud: "gcp", Region: "europe-west1", Short: "ew1", Fixed: "ew1"},
+	{Cloud: "gcp", Region: "europe-west2", Short: "ew2", Fixed: "ew2"},
+	{Cloud: "gcp", Region: "europe-west3", Short: "ew3", Fixed: "ew3"},
+	{Cloud: "gcp", Region: "europe-west4", Short: "ew4", Fixed: "ew4"},
+	{Cloud: "gcp", Region: "europe-west6", Short: "ew6", Fixed: "ew6"},
+	{Cloud: "gcp", Region: "europe-west8", Short: "ew8", Fixed: "ew8"},
+	{Cloud: "gcp", Region: "europe-west9", Short: "ew9", Fixed: "ew9"},
+	{Cloud: "gcp", Region: "europe-west10", Short: "ew10", Fixed: "e10"},
+	{Cloud: "gcp", Region: "europe-west12", Short: "ew12", Fixed: "e12"},
+	{Cloud: "gcp", Region: "asia-east1", Short: "ae1", Fixed: "ae1"},
+	{Cloud: "gcp", Region: "asia-east2", Short: "ae2", Fixed: "ae2"},
+	{Cloud: "gcp", Region: "asia-northeast1", Short: "ane1", Fixed: "an1"},
+	{Cloud: "gcp", Region: "asia-northeast2", Short: "ane2", Fixed: "an2"},
+	{Cloud: "gcp", Region: "asia-northeast3", Short: "ane3", Fixed: "an3"},
+	{Cloud: "gcp", Region: "asia-south1", Short: "aso1", Fixed: "ai1"},
+	{Cloud: "gcp", Region: "asia-south2", Short: "aso2", Fixed: "ai2"},
+	{Cloud: "gcp", Region: "asia-southeast1", Short: "ase1", Fixed: "as1"},
+	{Cloud: "gcp", Region: "asia-southeast2", Short: "ase2", Fixed: "as2"},
+	{Cloud: "gcp", Region: "australia-southeast1", Short: "ose1", Fixed: "os1"},
+	{Cloud: "gcp", Region: "australia-southeast2", Short: "ose2", Fixed: "os2"},
+	{Cloud: "gcp", Region: "me-central1", Short: "mc1", Fixed: "mc1"},
+	{Cloud: "gcp", Region: "me-central2", Short: "mc2", Fixed: "mc2"},
+	{Cloud: "gcp", Region: "me-west1", Short: "mw1", Fixed: "mw1"},
+	{Cloud: "gcp", Region: "africa-south1", Short: "fs1", Fixed: "fs1"},
+	// Azure
+	{Cloud: "azure", Region: "eastus", Short: "eus", Fixed: "eus"},
+	{Cloud: "azure", Region: "eastus2", Short: "eus2", Fixed: "eu2"},
+	{Cloud: "azure", Region: "westus", Short: "wus", Fixed: "wus"},
+	{Cloud: "azure", Region: "westus2", Short: "wus2", Fixed: "wu2"},
+	{Cloud: "azure", Region: "westus3", Short: "wus3", Fixed: "wu3"},
+	{Cloud: "azure", Region: "centralus", Short: "cus", Fixed: "cus"},
+	{Cloud: "azure", Region: "northcentralus", Short: "ncus", Fixed: "ncu"},
+	{Cloud: "azure", Region: "southcentralus", Short: "scus", Fixed: "scu"},
+	{Cloud: "azure", Region: "westcentralus", Short: "wcus", Fixed: "wcu"},
+	{Cloud: "azure", Region: "canadacentral", Short: "cnc", Fixed: "cnc"},
+	{Cloud: "azure", Region: "canadaeast", Short: "cne", Fixed: "cne"},
+	{Cloud: "azure", Region: "brazilsouth", Short: "brs", Fixed: "brs"},
+	{Cloud: "azure", Region: "mexicocentral", Short: "mxc", Fixed: "mxc"},
+	{Cloud: "azure", Region: "northeurope", Short: "neu", Fixed: "neu"},
+	{Cloud: "azure", Region: "westeurope", Short: "weu", Fixed: "weu"},
+	{Cloud: "azure", Region: "uksouth", Short: "uks", Fixed: "uks"},
+	{Cloud: "azure", Region: "ukwest", Short: "ukw", Fixed: "ukw"},
+	{Cloud: "azure", Region: "francecentral", Short: "frc", Fixed: "frc"},
+	{Cloud: "azure", Region: "germanywestcentral", Short: "gwc", Fixed: "gwc"},
+	{Cloud: "azure", Region: "switzerlandnorth", Short: "szn", Fixed: "szn"},
+	{Cloud: "azure", Region: "norwayeast", Short: "nwe", Fixed: "nwe"},
+	{Cloud: "azure", Region: "swedencentral", Short: "sdc", Fixed: "sdc"},
+	{Cloud: "azure", Region: "polandcentral", Short: "plc", Fixed: "plc"},
+	{Cloud: "azure", Region: "italynorth", Short: "itn", Fixed: "itn"},
+	{Cloud: "azure", Region: "spaincentral", Short: "spc", Fixed: "spc"},
+	{Cloud: "azure", Region: "eastasia", Short: "ea", Fixed: "eas"},
+	{Cloud: "azure", Region: "southeastasia", Short: "sea", Fixed: "sea"},
+	{Cloud: "azure", Region: "japaneast", Short: "jpe", Fixed: "jpe"},
+	{Cloud: "azure", Region: "japanwest", Short: "jpw", Fixed: "jpw"},
+	{Cloud: "azure", Region: "koreacentral", Short: "krc", Fixed: "krc"},
+	{Cloud: "azure", Region: "koreasouth", Short: "krs", Fixed: "krs"},
+	{Cloud: "azure", Region: "australiaeast", Short: "ae", Fixed: "aue"},
+	{Cloud: "azure", Region: "australiasoutheast", Short: "ase", Fixed: "ase"},
+	{Cloud: "azure", Region: "australiacentral", Short: "acl", Fixed: "acl"},
+	{Cloud: "azure", Region: "centralindia", Short: "inc", Fixed: "inc"},
+	{Cloud: "azure", Region: "southindia", Short: "ins", Fixed: "ins"},
+	{Cloud: "azure", Region: "westindia", Short: "inw", Fixed: "inw"},
+	{Cloud: "azure", Region: "uaenorth", Short: "uan", Fixed: "uan"},
+	{Cloud: "azure", Region: "qatarcentral", Short: "qac", Fixed: "qac"},
+	{Cloud: "azure", Region: "israelcentral", Short: "ilc", Fixed: "ilc"},
+	{Cloud: "azure", Region: "southafricanorth", Short: "san", Fixed: "san"},
+}
+
+// RegionTable maps cloud region names to abbreviations and back.
+type RegionTable struct {
+	codes []RegionCode
+}
+
+// NewRegionTable returns the built-in table extended with custom codes keyed
+// by region name. A custom code is used for both styles and takes precedence
+// over a built-in entry for the same region.
+func NewRegionTable(custom map[string]string) *RegionTable {
+	t := &RegionTable{}
+
+	regions := make([]string, 0, len(custom))
+	for region := range custom {
+		regions = append(regions, region)
+	}
+	sort.Strings(regions)
+	for _, region := range regions {
+		code := custom[region]
+		t.codes = append(t.codes, RegionCode{Cloud: "custom", Region: region, Short: code, Fixed: code})
+	}
+
+	t.codes = append(t.codes, builtinRegionCodes...)
+	return t
+}
+
+// Lookup returns the entry for a region name.
+func (t *RegionTable) Lookup(region string) (RegionCode, bool) {
+	for _, r := range t.entries() {
+		if r.Region == region {
+			return r, true
+		}
+	}
+	return RegionCode{}, false
+}
+
+// Reverse returns the entry whose code matches within the given cloud. Short
+// codes are matched before fixed codes, and custom codes match any cloud.
+func (t *RegionTable) Reverse(code string, cloud string) (RegionCode, bool) {
+	r, _, ok := t.ReverseStyle(code, cloud, "")
+	return r, ok
+}
+
+// ReverseStyle is Reverse that also returns the style of the matched code.
+// A non-empty style only matches codes of that style.
+// e.g. ("an2", "aws", "") → (ap-northeast-2, "fixed")
+func (t *RegionTable) ReverseStyle(code string, cloud string, style string) (RegionCode, string, bool) {
+	styles := []string{RegionStyleShort, RegionStyleFixed}
+	if style != "" {
+		styles = []string{style}
+	}
+	for _, style := range styles {
+		for _, r := range t.entries() {
+			if (r.Cloud == cloud || r.Cloud == "custom") && r.Code(style) == code {
+				return r, style, true
+			}
+		}
+	}
+	return RegionCode{}, "", false
+}
+
+func (t *RegionTable) entries() []RegionCode {
+	if t == nil {
+		return builtinRegionCodes
+	}
+	return t.codes
+}
+
+// RegionToCode converts a region name to its abbreviation.
+// e.g. ("ap-northeast-2", "short") → "ane2"
+func RegionToCode(t *RegionTable, region string, style string) (string, error) {
+	if err := validateRegionStyle(style); err != nil {
+		return "", err
+	}
+	r, ok := t.Lookup(region)
+	if !ok {
+		return "", fmt.Errorf("unknown region %q; add it to region_codes", region)
+	}
+	return r.Code(style), nil
+}
+
+// CodeToRegion converts an abbreviation back to the region name of a cloud.
+// e.g. ("ane2", "aws") → "ap-northeast-2"
+func CodeToRegion(t *RegionTable, code string, cloud string) (string, error) {
+	if err := validateRegionCloud(cloud); err != nil {
+		return "", err
+	}
+	r, ok := t.Reverse(code, cloud)
+	if !ok {
+		return "", fmt.Errorf("unknown %s region code %q", cloud, code)
+	}
+	return r.Region, nil
+}
+
+func validateRegionStyle(style string) error {
+	if style != RegionStyleShort && style != RegionStyleFixed {
+		return fmt.Errorf("invalid region style %q; expected %q or %q", style, RegionStyleShort, RegionStyleFixed)
+	}
+	return nil
+}
+
+func validateRegionCloud(cloud string) error {
+	switch cloud {
+	case "aws", "gcp", "azure":
+		return nil
+	}
+	return fmt.Errorf("invalid cloud %q; expected aws, gcp or azure", cloud)
+}
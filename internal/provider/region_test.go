@@ -0,0 +1,151 @@
+package provider
+
+import (
+	"testing"
+)
+
+func TestRegionToCode(t *testing.T) {
+	tests := []struct {
+		region string
+		style  string
+		want   string
+	}{
+		{"ap-northeast-2", RegionStyleShort, "ane2"},
+		{"ap-northeast-2", RegionStyleFixed, "an2"},
+		{"us-east-1", RegionStyleShort, "ue1"},
+		{"ap-south-1", RegionStyleShort, "aso1"},
+		{"ap-south-1", RegionStyleFixed, "ai1"},
+		{"asia-northeast3", RegionStyleShort, "ane3"},
+		{"europe-west10", RegionStyleFixed, "e10"},
+		{"koreacentral", RegionStyleShort, "krc"},
+		{"eastus2", RegionStyleFixed, "eu2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.region+"/"+tt.style, func(t *testing.T) {
+			got, err := RegionToCode(nil, tt.region, tt.style)
+			if err != nil {
+				t.Fatalf("RegionToCode(%q, %q) error: %v", tt.region, tt.style, err)
+			}
+			if got != tt.want {
+				t.Errorf("RegionToCode(%q, %q) = %q, want %q", tt.region, tt.style, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRegionToCode_Errors(t *testing.T) {
+	if _, err := RegionToCode(nil, "mars-north-1", RegionStyleShort); err == nil {
+		t.Error("expected error for unknown region")
+	}
+	if _, err := RegionToCode(nil, "ap-northeast-2", "long"); err == nil {
+		t.Error("expected error for invalid style")
+	}
+}
+
+func TestCodeToRegion(t *testing.T) {
+	tests := []struct {
+		code  string
+		cloud string
+		want  string
+	}{
+		{"ane2", "aws", "ap-northeast-2"},
+		{"an2", "aws", "ap-northeast-2"},
+		{"ane2", "gcp", "asia-northeast2"},
+		{"ue1", "aws", "us-east-1"},
+		{"krc", "azure", "koreacentral"},
+		{"eu2", "azure", "eastus2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.cloud+"/"+tt.code, func(t *testing.T) {
+			got, err := CodeToRegion(nil, tt.code, tt.cloud)
+			if err != nil {
+				t.Fatalf("CodeToRegion(%q, %q) error: %v", tt.code, tt.cloud, err)
+			}
+			if got != tt.want {
+				t.Errorf("CodeToRegion(%q, %q) = %q, want %q", tt.code, tt.cloud, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRegionTable_Custom(t *testing.T) {
+	table := NewRegionTable(map[string]string{
+		"ap-northeast-2": "kr1",
+		"on-prem-seoul":  "ops",
+	})
+
+	tests := []struct {
+		region string
+		want   string
+	}{
+		{"ap-northeast-2", "kr1"},
+		{"on-prem-seoul", "ops"},
+		{"us-east-1", "ue1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.region, func(t *testing.T) {
+			got, err := RegionToCode(table, tt.region, RegionStyleShort)
+			if err != nil {
+				t.Fatalf("RegionToCode(%q) error: %v", tt.region, err)
+			}
+			if got != tt.want {
+				t.Errorf("RegionToCode(%q) = %q, want %q", tt.region, got, tt.want)
+			}
+		})
+	}
+
+	if got, err := CodeToRegion(table, "ops", "aws"); err != nil || got != "on-prem-seoul" {
+		t.Errorf("CodeToRegion(ops) = %q, %v", got, err)
+	}
+}
+
+func TestRegionTable_ReverseStyle(t *testing.T) {
+	table := NewRegionTable(map[string]string{"on-prem-seoul": "ops"})
+
+	tests := []struct {
+		code       string
+		cloud      string
+		style      string
+		wantRegion string
+		wantStyle  string
+		wantOK     bool
+	}{
+		{"ane2", "aws", "", "ap-northeast-2", RegionStyleShort, true},
+		{"an2", "aws", "", "ap-northeast-2", RegionStyleFixed, true},
+		{"ue1", "aws", "", "us-east-1", RegionStyleShort, true},
+		{"ue1", "aws", RegionStyleFixed, "us-east-1", RegionStyleFixed, true},
+		{"an2", "aws", RegionStyleShort, "", "", false},
+		{"ops", "gcp", "", "on-prem-seoul", RegionStyleShort, true},
+		{"ane2", "azure", "", "", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.code+"/"+tt.cloud+"/"+tt.style, func(t *testing.T) {
+			r, style, ok := table.ReverseStyle(tt.code, tt.cloud, tt.style)
+			if ok != tt.wantOK || r.Region != tt.wantRegion || style != tt.wantStyle {
+				t.Errorf("ReverseStyle(%q, %q, %q) = %q, %q, %v, want %q, %q, %v", tt.code, tt.cloud, tt.style, r.Region, style, ok, tt.wantRegion, tt.wantStyle, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestBuiltinRegionCodes_Unique(t *testing.T) {
+	type key struct{ cloud, code string }
+	owners := map[key]string{}
+
+	for _, r := range builtinRegionCodes {
+		if len(r.Fixed) != 3 {
+			t.Errorf("%s fixed code %q is not three characters", r.Region, r.Fixed)
+		}
+		for _, code := range []string{r.Short, r.Fixed} {
+			k := key{r.Cloud, code}
+			if owner, ok := owners[k]; ok && owner != r.Region {
+				t.Errorf("%s code %q used by both %s and %s", r.Cloud, code, owner, r.Region)
+			}
+			owners[k] = r.Region
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package provider
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// IDRegistry records the IDs generated during a provider run so that
+// duplicates can be reported. It is safe for concurrent use, as Terraform
+// reads data sources in parallel.
+type IDRegistry struct {
+	mu  sync.Mutex
+	ids map[string]string
+}
+
+// NewIDRegistry returns an empty registry.
+func NewIDRegistry() *IDRegistry {
+	return &IDRegistry{ids: map[string]string{}}
+}
+
+// Register records that owner generated id. When id was already registered,
+// it returns the first owner and true.
+func (r *IDRegistry) Register(id string, owner string) (string, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if prev, ok := r.ids[id]; ok {
+		return prev, true
+	}
+	r.ids[id] = owner
+	return "", false
+}
+
+// Kinds of owners checkDuplicate registers IDs for. IDs are only compared
+// within a kind: a label_name resource is usually paired with a label data
+// source with the same inputs for its tags, so one read and one pinned
+// resource per ID is expected.
+const (
+	ownerRead   = "read"
+	ownerPinned = "pinned"
+)
+
+// checkDuplicate registers id for owner, of the given kind, in the registry
+// of cfg and reports a repeated ID as a warning, or an error with
+// duplicate_check = "error". It returns false when the read or plan must
+// fail.
+func checkDuplicate(cfg *LabelConfig, kind string, id string, owner string, diags *diag.Diagnostics) bool {
+	if cfg.Registry == nil || cfg.DuplicateCheck == CheckOff {
+		return true
+	}
+	prev, dup := cfg.Registry.Register(kind+"\x00"+id, owner)
+	if !dup {
+		return true
+	}
+
+	summary := "Duplicate Label ID"
+	generated := fmt.Sprintf("both %s and %s", prev, owner)
+	if prev == owner {
+		generated = fmt.Sprintf("two blocks with the same inputs, %s", owner)
+	}
+	detail := fmt.Sprintf(
+		"ID %q is generated by %s. Resources named with it will collide when applied.\n\n"+
+			"Terraform does not pass block addresses to providers, so the blocks are described by their inputs; "+
+			"look for them in the configuration, including count and for_each instances.\n\n"+
+			"Set a qualifier or instance_key to tell them apart.",
+		id, generated,
+	)
+	if cfg.DuplicateCheck == CheckError {
+		diags.AddError(summary, detail)
+		return false
+	}
+	diags.AddWarning(summary, detail)
+	return true
+}
+
+// labelInput is a named argument of a block, for describeBlock.
+type labelInput struct {
+	name  string
+	value types.String
+}
+
+// describeBlock identifies a block by its kind, e.g. `resource "label_name"`,
+// and the arguments that are set, since the framework does not expose the
+// Terraform address of the block.
+func describeBlock(kind string, inputs []labelInput) string {
+	var parts []string
+	for _, in := range inputs {
+		if !in.value.IsNull() && !in.value.IsUnknown() {
+			parts = append(parts, fmt.Sprintf("%s = %q", in.name, in.value.ValueString()))
+		}
+	}
+	return fmt.Sprintf("%s { %s }", kind, strings.Join(parts, ", "))
+}
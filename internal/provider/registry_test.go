@@ -0,0 +1,102 @@
+package provider
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestIDRegistry_Register(t *testing.T) {
+	r := NewIDRegistry()
+
+	if _, dup := r.Register("dpl-ane2-sg-dev-emr", "a"); dup {
+		t.Fatal("first registration reported as duplicate")
+	}
+	if _, dup := r.Register("dpl-ane2-sg-dev-msk", "b"); dup {
+		t.Fatal("distinct ID reported as duplicate")
+	}
+	prev, dup := r.Register("dpl-ane2-sg-dev-emr", "c")
+	if !dup {
+		t.Fatal("duplicate ID not reported")
+	}
+	if prev != "a" {
+		t.Errorf("previous owner = %q, want %q", prev, "a")
+	}
+}
+
+func TestIDRegistry_Concurrent(t *testing.T) {
+	r := NewIDRegistry()
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	dups := 0
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if _, dup := r.Register(fmt.Sprintf("id-%d", i%50), fmt.Sprintf("owner-%d", i)); dup {
+				mu.Lock()
+				dups++
+				mu.Unlock()
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if dups != 50 {
+		t.Errorf("duplicates = %d, want 50", dups)
+	}
+}
+
+func TestCheckDuplicate(t *testing.T) {
+	emr := describeBlock(`data "label"`, []labelInput{{"resource_type", types.StringValue("sg")}, {"qualifier", types.StringValue("emr")}, {"instance_key", types.StringNull()}})
+	emrDev := describeBlock(`data "label"`, []labelInput{{"resource_type", types.StringValue("sg")}, {"qualifier", types.StringValue("emr")}, {"stage", types.StringValue("dev")}})
+	pinned := describeBlock(`resource "label_name"`, []labelInput{{"resource_type", types.StringValue("sg")}, {"qualifier", types.StringValue("emr")}})
+	if want := `data "label" { resource_type = "sg", qualifier = "emr" }`; emr != want {
+		t.Fatalf("describeBlock = %q, want %q", emr, want)
+	}
+
+	type registration struct{ kind, owner string }
+	tests := []struct {
+		name      string
+		mode      string
+		owners    []registration
+		wantOK    bool
+		wantError bool
+		want      string
+	}{
+		{name: "distinct blocks", mode: CheckWarn, owners: []registration{{ownerRead, emr}, {ownerRead, emrDev}}, wantOK: true, want: "both " + emr + " and " + emrDev},
+		{name: "same inputs", mode: CheckWarn, owners: []registration{{ownerRead, emr}, {ownerRead, emr}}, wantOK: true, want: "two blocks with the same inputs, " + emr},
+		{name: "read and pinned resource", mode: CheckError, owners: []registration{{ownerRead, emr}, {ownerPinned, pinned}}, wantOK: true},
+		{name: "pinned resources", mode: CheckWarn, owners: []registration{{ownerPinned, pinned}, {ownerRead, emr}, {ownerPinned, pinned}}, wantOK: true, want: "two blocks with the same inputs, " + pinned},
+		{name: "error", mode: CheckError, owners: []registration{{ownerRead, emr}, {ownerRead, emrDev}}, wantError: true, want: "does not pass block addresses"},
+		{name: "off", mode: CheckOff, owners: []registration{{ownerRead, emr}, {ownerRead, emr}}, wantOK: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &LabelConfig{DuplicateCheck: tt.mode, Registry: NewIDRegistry()}
+			var diags diag.Diagnostics
+			ok := true
+			for _, r := range tt.owners {
+				ok = checkDuplicate(cfg, r.kind, "dpl-ane2-sg-dev-emr", r.owner, &diags)
+			}
+			if ok != tt.wantOK || diags.HasError() != tt.wantError {
+				t.Fatalf("ok = %v, diagnostics = %v", ok, diags)
+			}
+			if tt.want == "" {
+				if len(diags) > 0 {
+					t.Errorf("unexpected diagnostics: %v", diags)
+				}
+				return
+			}
+			if len(diags) != 1 || !strings.Contains(diags[0].Detail(), tt.want) {
+				t.Errorf("diagnostics = %v, want detail containing %q", diags, tt.want)
+			}
+		})
+	}
+}
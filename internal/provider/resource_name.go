@@ -0,0 +1,270 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/mapplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	_ resource.Resource                = (*NameResource)(nil)
+	_ resource.ResourceWithConfigure   = (*NameResource)(nil)
+	_ resource.ResourceWithModifyPlan  = (*NameResource)(nil)
+	_ resource.ResourceWithImportState = (*NameResource)(nil)
+)
+
+// NameResource pins a generated ID in state. The name is computed once on
+// create and only changes when keepers change, so a later change to the
+// naming convention does not rename live resources.
+type NameResource struct {
+	config *LabelConfig
+}
+
+type NameResourceModel struct {
+	ResourceType types.String `tfsdk:"resource_type"`
+	Qualifier    types.String `tfsdk:"qualifier"`
+	InstanceKey  types.String `tfsdk:"instance_key"`
+	Delimiter    types.String `tfsdk:"delimiter"`
+	Keepers      types.Map    `tfsdk:"keepers"`
+	Id           types.String `tfsdk:"id"`
+	Name         types.String `tfsdk:"name"`
+	Drift        types.Bool   `tfsdk:"drift"`
+}
+
+func NewNameResource() resource.Resource {
+	return &NameResource{}
+}
+
+func (r *NameResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_name"
+}
+
+func (r *NameResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Generates a resource ID once and keeps it in state until keepers change.",
+		Attributes: map[string]schema.Attribute{
+			"resource_type": schema.StringAttribute{
+				Required:    true,
+				Description: "The resource type abbreviation (e.g. sg, role, emr, db)",
+			},
+			"qualifier": schema.StringAttribute{
+				Optional:    true,
+				Description: "Qualifier segment (e.g. emr, msk)",
+			},
+			"instance_key": schema.StringAttribute{
+				Optional:    true,
+				Description: "Instance key for distinguishing multiple resources of the same type",
+			},
+			"delimiter": schema.StringAttribute{
+				Optional:    true,
+				Description: "Override the provider-level delimiter for this resource",
+			},
+			"keepers": schema.MapAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				Description: "Arbitrary values that, when changed, regenerate the name from the current convention",
+				PlanModifiers: []planmodifier.Map{
+					mapplanmodifier.RequiresReplaceIf(keepersChanged, "Replace when keepers change, unless the name was imported.", "Replace when keepers change, unless the name was imported."),
+				},
+			},
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "Same as name",
+			},
+			"name": schema.StringAttribute{
+				Computed:    true,
+				Description: "Name generated on create and kept until keepers change",
+			},
+			"drift": schema.BoolAttribute{
+				Computed:    true,
+				Description: "Whether the current naming convention would generate a different name",
+			},
+		},
+	}
+}
+
+func (r *NameResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	cfg, ok := req.ProviderData.(*LabelConfig)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Provider Data",
+			fmt.Sprintf("Expected *LabelConfig, got: %T", req.ProviderData),
+		)
+		return
+	}
+
+	r.config = cfg
+}
+
+// generate returns the name the current convention produces for model.
+// ok is false when the provider is not configured or required components
+// are missing.
+func (r *NameResource) generate(model NameResourceModel) (string, bool) {
+	if r.config == nil || len(r.config.MissingComponents()) > 0 {
+		return "", false
+	}
+	return GenerateID(
+		r.config,
+		model.ResourceType.ValueString(),
+		model.Qualifier.ValueString(),
+		model.InstanceKey.ValueString(),
+		model.Delimiter.ValueString(),
+	), true
+}
+
+func (r *NameResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing to plan on destroy.
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var plan NameResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if plan.ResourceType.IsUnknown() || plan.Qualifier.IsUnknown() || plan.InstanceKey.IsUnknown() || plan.Delimiter.IsUnknown() || plan.Keepers.IsUnknown() {
+		return
+	}
+
+	if r.config != nil {
+		if missing := r.config.MissingComponents(); len(missing) > 0 {
+			resp.Diagnostics.AddError("Incomplete Provider Configuration", missingComponentsDetail(missing))
+			return
+		}
+	}
+	current, ok := r.generate(plan)
+	if !ok {
+		return
+	}
+
+	name := current
+	if !req.State.Raw.IsNull() {
+		var state NameResourceModel
+		resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		// Keep the stored name unless keepers changed, which replaces the
+		// resource and takes the name from the current convention.
+		if plan.Keepers.Equal(state.Keepers) || imported(state) {
+			name = state.Name.ValueString()
+		}
+	}
+
+	if r.config != nil && !checkDuplicate(r.config, ownerPinned, name, describeName(plan), &resp.Diagnostics) {
+		return
+	}
+
+	plan.Id = types.StringValue(name)
+	plan.Name = types.StringValue(name)
+	plan.Drift = types.BoolValue(name != current)
+	resp.Diagnostics.Append(resp.Plan.Set(ctx, &plan)...)
+}
+
+// describeName identifies a label_name resource by its inputs.
+func describeName(model NameResourceModel) string {
+	return describeBlock(`resource "label_name"`, []labelInput{
+		{"resource_type", model.ResourceType},
+		{"qualifier", model.Qualifier},
+		{"instance_key", model.InstanceKey},
+		{"delimiter", model.Delimiter},
+	})
+}
+
+func (r *NameResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan NameResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if r.config == nil {
+		resp.Diagnostics.AddError(
+			"Provider Not Configured",
+			"The label provider must be configured with tenant, environment, stage, and workspace.",
+		)
+		return
+	}
+	if missing := r.config.MissingComponents(); len(missing) > 0 {
+		resp.Diagnostics.AddError("Incomplete Provider Configuration", missingComponentsDetail(missing))
+		return
+	}
+
+	name, _ := r.generate(plan)
+	plan.Id = types.StringValue(name)
+	plan.Name = types.StringValue(name)
+	plan.Drift = types.BoolValue(false)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *NameResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state NameResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Imported names have no inputs yet; drift is known after the next apply.
+	if imported(state) {
+		return
+	}
+	if current, ok := r.generate(state); ok {
+		state.Drift = types.BoolValue(state.Name.ValueString() != current)
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+func (r *NameResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, state NameResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	plan.Id = state.Id
+	plan.Name = state.Name
+	current, ok := r.generate(plan)
+	plan.Drift = types.BoolValue(ok && state.Name.ValueString() != current)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *NameResource) Delete(_ context.Context, _ resource.DeleteRequest, _ *resource.DeleteResponse) {
+	// The name only lives in state.
+}
+
+// imported reports whether state holds a name adopted by ImportState that
+// has not been applied with inputs yet.
+func imported(state NameResourceModel) bool {
+	return state.ResourceType.IsNull()
+}
+
+// keepersChanged requires replacement for keeper changes, except on the
+// first apply after import, when keepers are set for the first time.
+func keepersChanged(ctx context.Context, req planmodifier.MapRequest, resp *mapplanmodifier.RequiresReplaceIfFuncResponse) {
+	var resourceType types.String
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("resource_type"), &resourceType)...)
+	resp.RequiresReplace = !resourceType.IsNull()
+}
+
+// ImportState adopts an existing name, e.g. one created before the
+// convention was introduced.
+func (r *NameResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), req.ID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("name"), req.ID)...)
+}
@@ -0,0 +1,130 @@
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+)
+
+func testNameResourceConfig(workspace string, keeper string) string {
+	return testProviderConfig + `
+provider "label" {
+  tenant      = "dpl"
+  environment = "ane2"
+  stage       = "dev"
+  workspace   = "` + workspace + `"
+}
+
+resource "label_name" "db" {
+  resource_type = "db"
+  qualifier     = "orders"
+  keepers = {
+    generation = "` + keeper + `"
+  }
+}
+`
+}
+
+func TestNameResource_PinsName(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testNameResourceConfig("sales-api", "1"),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue("label_name.db", tfjsonpath.New("name"), knownvalue.StringExact("dpl-ane2-db-dev-orders-sales-api")),
+					statecheck.ExpectKnownValue("label_name.db", tfjsonpath.New("drift"), knownvalue.Bool(false)),
+				},
+			},
+			// The convention changes, but the stored name is kept.
+			{
+				Config: testNameResourceConfig("sales", "1"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("label_name.db", plancheck.ResourceActionNoop),
+					},
+				},
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue("label_name.db", tfjsonpath.New("name"), knownvalue.StringExact("dpl-ane2-db-dev-orders-sales-api")),
+					statecheck.ExpectKnownValue("label_name.db", tfjsonpath.New("drift"), knownvalue.Bool(true)),
+				},
+			},
+			// Changing keepers regenerates the name from the current convention.
+			{
+				Config: testNameResourceConfig("sales", "2"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("label_name.db", plancheck.ResourceActionReplace),
+					},
+				},
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue("label_name.db", tfjsonpath.New("name"), knownvalue.StringExact("dpl-ane2-db-dev-orders-sales")),
+					statecheck.ExpectKnownValue("label_name.db", tfjsonpath.New("drift"), knownvalue.Bool(false)),
+				},
+			},
+		},
+	})
+}
+
+func TestNameResource_DuplicateCheck(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testProviderConfig + `
+provider "label" {
+  tenant          = "dpl"
+  environment     = "ane2"
+  stage           = "dev"
+  workspace       = "sales-api"
+  duplicate_check = "error"
+}
+
+# A data source for the tags of a pinned name is not a duplicate.
+data "label" "db" {
+  resource_type = "db"
+  qualifier     = "orders"
+}
+
+resource "label_name" "db" {
+  resource_type = "db"
+  qualifier     = "orders"
+}
+
+resource "label_name" "db_copy" {
+  resource_type = "db"
+  qualifier     = "orders"
+}
+`,
+				ExpectError: regexp.MustCompile(`Duplicate Label ID`),
+			},
+		},
+	})
+}
+
+func TestNameResource_Import(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:        testNameResourceConfig("sales-api", "1"),
+				ResourceName:  "label_name.db",
+				ImportState:   true,
+				ImportStateId: "legacy-orders-db",
+				ImportStateCheck: func(states []*terraform.InstanceState) error {
+					if got := states[0].Attributes["name"]; got != "legacy-orders-db" {
+						return fmt.Errorf("name = %q, want %q", got, "legacy-orders-db")
+					}
+					return nil
+				},
+			},
+		},
+	})
+}
@@ -0,0 +1,295 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/mapplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	_ resource.Resource               = (*RandomResource)(nil)
+	_ resource.ResourceWithConfigure  = (*RandomResource)(nil)
+	_ resource.ResourceWithModifyPlan = (*RandomResource)(nil)
+)
+
+// RandomResource appends a random suffix to a generated ID. The suffix is
+// generated once with crypto/rand and kept in state until keepers, length
+// or charset change; the ID part follows the current convention.
+type RandomResource struct {
+	config *LabelConfig
+}
+
+type RandomResourceModel struct {
+	ResourceType types.String `tfsdk:"resource_type"`
+	Qualifier    types.String `tfsdk:"qualifier"`
+	InstanceKey  types.String `tfsdk:"instance_key"`
+	Delimiter    types.String `tfsdk:"delimiter"`
+	Length       types.Int64  `tfsdk:"length"`
+	Charset      types.String `tfsdk:"charset"`
+	MaxLength    types.Int64  `tfsdk:"max_length"`
+	Keepers      types.Map    `tfsdk:"keepers"`
+	Id           types.String `tfsdk:"id"`
+	Name         types.String `tfsdk:"name"`
+	Suffix       types.String `tfsdk:"suffix"`
+}
+
+func NewRandomResource() resource.Resource {
+	return &RandomResource{}
+}
+
+func (r *RandomResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_random"
+}
+
+func (r *RandomResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Generates a resource ID with a random suffix that is kept until keepers change.",
+		Attributes: map[string]schema.Attribute{
+			"resource_type": schema.StringAttribute{
+				Required:    true,
+				Description: "The resource type abbreviation (e.g. sg, role, emr, db)",
+			},
+			"qualifier": schema.StringAttribute{
+				Optional:    true,
+				Description: "Qualifier segment (e.g. emr, msk)",
+			},
+			"instance_key": schema.StringAttribute{
+				Optional:    true,
+				Description: "Instance key for distinguishing multiple resources of the same type",
+			},
+			"delimiter": schema.StringAttribute{
+				Optional:    true,
+				Description: "Override the provider-level delimiter for this resource",
+			},
+			"length": schema.Int64Attribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     int64default.StaticInt64(8),
+				Description: fmt.Sprintf("Length of the random suffix (default: 8, max: %d). Changing it generates a new suffix.", MaxSuffixLength),
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+			"charset": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     stringdefault.StaticString(DefaultSuffixAlphabet),
+				Description: "Characters the random suffix is drawn from (default: a-z0-9). Changing it generates a new suffix.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"max_length": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Maximum length of name. The ID part is shortened so that the suffix is always kept whole.",
+			},
+			"keepers": schema.MapAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				Description: "Arbitrary values that, when changed, generate a new suffix",
+				PlanModifiers: []planmodifier.Map{
+					mapplanmodifier.RequiresReplace(),
+				},
+			},
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "Same as name",
+			},
+			"name": schema.StringAttribute{
+				Computed:    true,
+				Description: "Generated ID joined with the random suffix",
+			},
+			"suffix": schema.StringAttribute{
+				Computed:    true,
+				Description: "Random suffix generated on create",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+func (r *RandomResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	cfg, ok := req.ProviderData.(*LabelConfig)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Provider Data",
+			fmt.Sprintf("Expected *LabelConfig, got: %T", req.ProviderData),
+		)
+		return
+	}
+
+	r.config = cfg
+}
+
+// compose joins the ID the current convention produces for model with
+// suffix. ok is false when the provider is not configured or required
+// components are missing.
+func (r *RandomResource) compose(model RandomResourceModel, suffix string) (string, bool, error) {
+	if r.config == nil || len(r.config.MissingComponents()) > 0 {
+		return "", false, nil
+	}
+
+	delimiter := model.Delimiter.ValueString()
+	id := GenerateID(r.config, model.ResourceType.ValueString(), model.Qualifier.ValueString(), model.InstanceKey.ValueString(), delimiter)
+	if delimiter == "" {
+		delimiter = r.config.Delimiter
+	}
+
+	name, err := ComposeName(id, suffix, delimiter, int(model.MaxLength.ValueInt64()))
+	return name, true, err
+}
+
+func (r *RandomResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing to plan on destroy.
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var plan RandomResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !plan.Length.IsUnknown() && !plan.Charset.IsUnknown() {
+		if err := validateSuffix(int(plan.Length.ValueInt64()), plan.Charset.ValueString()); err != nil {
+			resp.Diagnostics.AddError("Invalid Random Suffix", err.Error())
+			return
+		}
+	}
+
+	if r.config != nil {
+		if missing := r.config.MissingComponents(); len(missing) > 0 {
+			resp.Diagnostics.AddError("Incomplete Provider Configuration", missingComponentsDetail(missing))
+			return
+		}
+	}
+
+	if plan.ResourceType.IsUnknown() || plan.Qualifier.IsUnknown() || plan.InstanceKey.IsUnknown() || plan.Delimiter.IsUnknown() || plan.MaxLength.IsUnknown() || plan.Length.IsUnknown() {
+		return
+	}
+
+	// The name is only known once the suffix exists, i.e. after create.
+	// Until then, check max_length against a placeholder of the same length.
+	suffix := plan.Suffix.ValueString()
+	if plan.Suffix.IsUnknown() {
+		suffix = strings.Repeat("x", int(plan.Length.ValueInt64()))
+	}
+	name, ok, err := r.compose(plan, suffix)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("max_length"), "Invalid Max Length", err.Error())
+		return
+	}
+	if !ok || plan.Suffix.IsUnknown() {
+		return
+	}
+	if !checkDuplicate(r.config, ownerPinned, name, describeRandom(plan), &resp.Diagnostics) {
+		return
+	}
+
+	plan.Id = types.StringValue(name)
+	plan.Name = types.StringValue(name)
+	resp.Diagnostics.Append(resp.Plan.Set(ctx, &plan)...)
+}
+
+// describeRandom identifies a label_random resource by its inputs.
+func describeRandom(model RandomResourceModel) string {
+	return describeBlock(`resource "label_random"`, []labelInput{
+		{"resource_type", model.ResourceType},
+		{"qualifier", model.Qualifier},
+		{"instance_key", model.InstanceKey},
+		{"delimiter", model.Delimiter},
+	})
+}
+
+func (r *RandomResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan RandomResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if r.config == nil {
+		resp.Diagnostics.AddError(
+			"Provider Not Configured",
+			"The label provider must be configured with tenant, environment, stage, and workspace.",
+		)
+		return
+	}
+
+	suffix, err := RandomSuffix(int(plan.Length.ValueInt64()), plan.Charset.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Random Suffix Generation Failed", err.Error())
+		return
+	}
+
+	name, ok, err := r.compose(plan, suffix)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("max_length"), "Invalid Max Length", err.Error())
+		return
+	}
+	if !ok {
+		resp.Diagnostics.AddError("Incomplete Provider Configuration", missingComponentsDetail(r.config.MissingComponents()))
+		return
+	}
+	// New names are only known here, after the plan.
+	if !checkDuplicate(r.config, ownerPinned, name, describeRandom(plan), &resp.Diagnostics) {
+		return
+	}
+
+	plan.Id = types.StringValue(name)
+	plan.Name = types.StringValue(name)
+	plan.Suffix = types.StringValue(suffix)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *RandomResource) Read(_ context.Context, _ resource.ReadRequest, _ *resource.ReadResponse) {
+	// Everything lives in state; the name is recomputed at plan time.
+}
+
+func (r *RandomResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, state RandomResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	name, ok, err := r.compose(plan, state.Suffix.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("max_length"), "Invalid Max Length", err.Error())
+		return
+	}
+	if !ok {
+		resp.Diagnostics.AddError("Incomplete Provider Configuration", missingComponentsDetail(r.config.MissingComponents()))
+		return
+	}
+
+	plan.Id = types.StringValue(name)
+	plan.Name = types.StringValue(name)
+	plan.Suffix = state.Suffix
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *RandomResource) Delete(_ context.Context, _ resource.DeleteRequest, _ *resource.DeleteResponse) {
+	// The suffix only lives in state.
+}
@@ -0,0 +1,60 @@
+package provider
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/compare"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+)
+
+func testRandomResourceConfig(qualifier string, keeper string) string {
+	return testProviderConfigWithValues + `
+resource "label_random" "logs" {
+  resource_type = "s3"
+  qualifier     = "` + qualifier + `"
+  length        = 6
+  max_length    = 33
+  keepers = {
+    generation = "` + keeper + `"
+  }
+}
+`
+}
+
+func TestRandomResource_Basic(t *testing.T) {
+	sameSuffix := statecheck.CompareValue(compare.ValuesSame())
+	newSuffix := statecheck.CompareValue(compare.ValuesDiffer())
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testRandomResourceConfig("logs", "1"),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue("label_random.logs", tfjsonpath.New("name"), knownvalue.StringRegexp(regexp.MustCompile(`^dpl-ane2-s3-dev-logs-sales-[a-z0-9]{6}$`))),
+					sameSuffix.AddStateValue("label_random.logs", tfjsonpath.New("suffix")),
+					newSuffix.AddStateValue("label_random.logs", tfjsonpath.New("suffix")),
+				},
+			},
+			// Other inputs change the ID part but keep the suffix.
+			{
+				Config: testRandomResourceConfig("audit", "1"),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue("label_random.logs", tfjsonpath.New("name"), knownvalue.StringRegexp(regexp.MustCompile(`^dpl-ane2-s3-dev-audit-sale-[a-z0-9]{6}$`))),
+					sameSuffix.AddStateValue("label_random.logs", tfjsonpath.New("suffix")),
+				},
+			},
+			// Changing keepers generates a new suffix.
+			{
+				Config: testRandomResourceConfig("audit", "2"),
+				ConfigStateChecks: []statecheck.StateCheck{
+					newSuffix.AddStateValue("label_random.logs", tfjsonpath.New("suffix")),
+				},
+			},
+		},
+	})
+}
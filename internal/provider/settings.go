@@ -0,0 +1,554 @@
+package provider
+
+import (
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Settings hold the provider settings from one source (the provider block,
+// LABEL_* environment variables or a convention file) before they are
+// resolved into a LabelConfig. Nil fields are unset.
+type Settings struct {
+	Tenant      *string `json:"tenant,omitempty"`
+	Environment *string `json:"environment,omitempty"`
+	Stage       *string `json:"stage,omitempty"`
+	Workspace   *string `json:"workspace,omitempty"`
+	Namespace   *string `json:"namespace,omitempty"`
+	Delimiter   *string `json:"delimiter,omitempty"`
+
+	Region      *string           `json:"region,omitempty"`
+	RegionStyle *string           `json:"region_style,omitempty"`
+	RegionCodes map[string]string `json:"region_codes,omitempty"`
+
+	Components         map[string]string `json:"components,omitempty"`
+	LabelOrder         []string          `json:"label_order,omitempty"`
+	RequiredComponents []string          `json:"required_components,omitempty"`
+
+	WorkspaceDelimiter     *string           `json:"workspace_delimiter,omitempty"`
+	WorkspaceOpaque        *bool             `json:"workspace_opaque,omitempty"`
+	WorkspaceAbbreviations map[string]string `json:"workspace_abbreviations,omitempty"`
+	WorkspaceFromPath      *string           `json:"workspace_from_path,omitempty"`
+	WorkspaceDetectors     []string          `json:"workspace_detectors,omitempty"`
+	WorkspacePattern       *string           `json:"workspace_pattern,omitempty"`
+
+	AttributesDelimiter  *string  `json:"attributes_delimiter,omitempty"`
+	AttributesComponents []string `json:"attributes_components,omitempty"`
+	AttributesTag        *bool    `json:"attributes_tag,omitempty"`
+
+	AmbiguityCheck  *string `json:"ambiguity_check,omitempty"`
+	EscapeDelimiter *bool   `json:"escape_delimiter,omitempty"`
+	DuplicateCheck  *string `json:"duplicate_check,omitempty"`
+	SuffixSalt      *string `json:"suffix_salt,omitempty"`
+
+	LegacyNames map[string]string `json:"legacy_names,omitempty"`
+
+	Aliases      map[string]map[string]string `json:"aliases,omitempty"`
+	RawValueTags *bool                        `json:"raw_value_tags,omitempty"`
+
+	Compat            *string  `json:"compat,omitempty"`
+	IDLengthLimit     *int64   `json:"id_length_limit,omitempty"`
+	RegexReplaceChars *string  `json:"regex_replace_chars,omitempty"`
+	LabelKeyCase      *string  `json:"label_key_case,omitempty"`
+	LabelValueCase    *string  `json:"label_value_case,omitempty"`
+	LabelsAsTags      []string `json:"labels_as_tags,omitempty"`
+}
+
+// SettingError is an invalid provider setting.
+type SettingError struct {
+	Attribute string // provider attribute, empty when not tied to one
+	Summary   string
+	Detail    string
+}
+
+func (e SettingError) Error() string {
+	if e.Attribute == "" {
+		return fmt.Sprintf("%s: %s", e.Summary, e.Detail)
+	}
+	return fmt.Sprintf("%s: %s: %s", e.Attribute, e.Summary, e.Detail)
+}
+
+// Environ converts os.Environ-style KEY=value pairs to a map.
+func Environ(kvs []string) map[string]string {
+	env := make(map[string]string, len(kvs))
+	for _, kv := range kvs {
+		if key, value, ok := strings.Cut(kv, "="); ok {
+			env[key] = value
+		}
+	}
+	return env
+}
+
+// component returns the builtin component setting called name.
+func (s *Settings) component(name string) *string {
+	switch name {
+	case "tenant":
+		return s.Tenant
+	case "environment":
+		return s.Environment
+	case "stage":
+		return s.Stage
+	case "workspace":
+		return s.Workspace
+	case "namespace":
+		return s.Namespace
+	}
+	return nil
+}
+
+// EnvSettings reads the LABEL_* environment variables. Empty variables are
+// treated as unset.
+func EnvSettings(env map[string]string) (Settings, []SettingError) {
+	var errs []SettingError
+	str := func(key string) *string {
+		if v := env[key]; v != "" {
+			return &v
+		}
+		return nil
+	}
+	list := func(key string) []string {
+		var out []string
+		for _, item := range strings.Split(env[key], ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				out = append(out, item)
+			}
+		}
+		return out
+	}
+	boolean := func(attr string, key string, summary string) *bool {
+		v := env[key]
+		if v == "" {
+			return nil
+		}
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			errs = append(errs, SettingError{attr, summary, fmt.Sprintf("%s: %s", key, err)})
+			return nil
+		}
+		return &b
+	}
+	integer := func(attr string, key string, summary string) *int64 {
+		v := env[key]
+		if v == "" {
+			return nil
+		}
+		i, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			errs = append(errs, SettingError{attr, summary, fmt.Sprintf("%s: %s", key, err)})
+			return nil
+		}
+		return &i
+	}
+
+	s := Settings{
+		Tenant:               str("LABEL_TENANT"),
+		Environment:          str("LABEL_ENVIRONMENT"),
+		Stage:                str("LABEL_STAGE"),
+		Workspace:            str("LABEL_WORKSPACE"),
+		Namespace:            str("LABEL_NAMESPACE"),
+		Delimiter:            str("LABEL_DELIMITER"),
+		Region:               str("LABEL_REGION"),
+		RegionStyle:          str("LABEL_REGION_STYLE"),
+		LabelOrder:           list("LABEL_ORDER"),
+		RequiredComponents:   list("LABEL_REQUIRED_COMPONENTS"),
+		WorkspaceDelimiter:   str("LABEL_WORKSPACE_DELIMITER"),
+		WorkspaceOpaque:      boolean("workspace_opaque", "LABEL_WORKSPACE_OPAQUE", "Invalid Workspace Opaque Value"),
+		WorkspaceFromPath:    str("LABEL_WORKSPACE_FROM_PATH"),
+		WorkspaceDetectors:   list("LABEL_WORKSPACE_DETECTORS"),
+		WorkspacePattern:     str("LABEL_WORKSPACE_PATTERN"),
+		AttributesDelimiter:  str("LABEL_ATTRIBUTES_DELIMITER"),
+		AttributesComponents: list("LABEL_ATTRIBUTES_COMPONENTS"),
+		AttributesTag:        boolean("attributes_tag", "LABEL_ATTRIBUTES_TAG", "Invalid Attributes Tag Value"),
+		AmbiguityCheck:       str("LABEL_AMBIGUITY_CHECK"),
+		EscapeDelimiter:      boolean("escape_delimiter", "LABEL_ESCAPE_DELIMITER", "Invalid Escape Delimiter Value"),
+		DuplicateCheck:       str("LABEL_DUPLICATE_CHECK"),
+		SuffixSalt:           str("LABEL_SUFFIX_SALT"),
+		Compat:               str("LABEL_COMPAT"),
+		IDLengthLimit:        integer("id_length_limit", "LABEL_ID_LENGTH_LIMIT", "Invalid ID Length Limit"),
+		RegexReplaceChars:    str("LABEL_REGEX_REPLACE_CHARS"),
+		LabelKeyCase:         str("LABEL_KEY_CASE"),
+		LabelValueCase:       str("LABEL_VALUE_CASE"),
+		LabelsAsTags:         list("LABEL_LABELS_AS_TAGS"),
+	}
+
+	for key, value := range env {
+		if name, ok := strings.CutPrefix(key, "LABEL_COMPONENT_"); ok && name != "" {
+			if s.Components == nil {
+				s.Components = map[string]string{}
+			}
+			s.Components[strings.ToLower(name)] = value
+		}
+	}
+	return s, errs
+}
+
+// MergeSettings combines layers in order of precedence: the first layer
+// that sets a value wins. Maps are merged key by key.
+func MergeSettings(layers ...Settings) Settings {
+	var out Settings
+	for i := len(layers) - 1; i >= 0; i-- {
+		l := layers[i]
+		setIf(&out.Tenant, l.Tenant)
+		setIf(&out.Environment, l.Environment)
+		setIf(&out.Stage, l.Stage)
+		setIf(&out.Workspace, l.Workspace)
+		setIf(&out.Namespace, l.Namespace)
+		setIf(&out.Delimiter, l.Delimiter)
+		setIf(&out.Region, l.Region)
+		setIf(&out.RegionStyle, l.RegionStyle)
+		out.RegionCodes = mergeMap(out.RegionCodes, l.RegionCodes)
+		out.Components = mergeMap(out.Components, l.Components)
+		setListIf(&out.LabelOrder, l.LabelOrder)
+		setListIf(&out.RequiredComponents, l.RequiredComponents)
+		setIf(&out.WorkspaceDelimiter, l.WorkspaceDelimiter)
+		setIf(&out.WorkspaceOpaque, l.WorkspaceOpaque)
+		out.WorkspaceAbbreviations = mergeMap(out.WorkspaceAbbreviations, l.WorkspaceAbbreviations)
+		setIf(&out.WorkspaceFromPath, l.WorkspaceFromPath)
+		setListIf(&out.WorkspaceDetectors, l.WorkspaceDetectors)
+		setIf(&out.WorkspacePattern, l.WorkspacePattern)
+		setIf(&out.AttributesDelimiter, l.AttributesDelimiter)
+		setListIf(&out.AttributesComponents, l.AttributesComponents)
+		setIf(&out.AttributesTag, l.AttributesTag)
+		setIf(&out.AmbiguityCheck, l.AmbiguityCheck)
+		setIf(&out.EscapeDelimiter, l.EscapeDelimiter)
+		setIf(&out.DuplicateCheck, l.DuplicateCheck)
+		setIf(&out.SuffixSalt, l.SuffixSalt)
+		out.LegacyNames = mergeMap(out.LegacyNames, l.LegacyNames)
+		out.Aliases = mergeMap(out.Aliases, l.Aliases)
+		setIf(&out.RawValueTags, l.RawValueTags)
+		setIf(&out.Compat, l.Compat)
+		setIf(&out.IDLengthLimit, l.IDLengthLimit)
+		setIf(&out.RegexReplaceChars, l.RegexReplaceChars)
+		setIf(&out.LabelKeyCase, l.LabelKeyCase)
+		setIf(&out.LabelValueCase, l.LabelValueCase)
+		setListIf(&out.LabelsAsTags, l.LabelsAsTags)
+	}
+	return out
+}
+
+func setIf[T any](dst **T, v *T) {
+	if v != nil {
+		*dst = v
+	}
+}
+
+func setListIf(dst *[]string, v []string) {
+	if v != nil {
+		*dst = v
+	}
+}
+
+func mergeMap[V any](dst map[string]V, src map[string]V) map[string]V {
+	if src == nil {
+		return dst
+	}
+	if dst == nil {
+		dst = map[string]V{}
+	}
+	maps.Copy(dst, src)
+	return dst
+}
+
+// ResolveConfig builds a LabelConfig from provider block settings, the
+// environment and convention file settings, in that order of precedence.
+// fileSource describes the convention file in component sources. getwd is
+// used by workspace_from_path.
+func ResolveConfig(config Settings, file Settings, fileSource string, env map[string]string, getwd func() (string, error)) (*LabelConfig, []SettingError) {
+	envSettings, errs := EnvSettings(env)
+	s := MergeSettings(config, envSettings, file)
+
+	fail := func(attr, summary, detail string) {
+		errs = append(errs, SettingError{attr, summary, detail})
+	}
+	str := func(v *string) string {
+		if v == nil {
+			return ""
+		}
+		return *v
+	}
+
+	delimiter := str(s.Delimiter)
+	if delimiter == "" {
+		delimiter = "-"
+	}
+
+	cfg := &LabelConfig{
+		Delimiter: delimiter,
+		Sources:   map[string]string{},
+	}
+	for _, name := range BuiltinComponents {
+		for _, layer := range []struct {
+			settings Settings
+			source   string
+		}{
+			{config, "config"},
+			{envSettings, "env " + ComponentEnvVar(name)},
+			{file, fileSource},
+		} {
+			v := layer.settings.component(name)
+			if v == nil {
+				continue
+			}
+			if *v != "" {
+				cfg.SetComponent(name, *v)
+				cfg.Sources[name] = layer.source
+			}
+			break
+		}
+	}
+
+	cfg.WorkspaceDelimiter = str(s.WorkspaceDelimiter)
+	cfg.WorkspaceOpaque = s.WorkspaceOpaque != nil && *s.WorkspaceOpaque
+	cfg.WorkspaceAbbreviations = s.WorkspaceAbbreviations
+
+	// declared are the custom components the configuration can set, even
+	// when the path or CI/CD variables do not provide them in this run.
+	declared := map[string]bool{}
+
+	if expr := str(s.WorkspaceFromPath); expr != "" {
+		var join string
+		if cfg.WorkspaceDelimiter != "" {
+			join = cfg.WorkspaceDelimiter[:1]
+		}
+		matcher, err := NewPathMatcher(expr, join)
+		if err != nil {
+			fail("workspace_from_path", "Invalid Workspace Path Pattern", err.Error())
+		} else {
+			for _, name := range matcher.Components() {
+				declared[name] = true
+			}
+		}
+		if err == nil && cfg.Workspace == "" {
+			if dir, err := getwd(); err != nil {
+				fail("", "Unable to Determine Working Directory", err.Error())
+			} else if components, ok := matcher.Match(dir); ok {
+				for name, value := range components {
+					if current, _ := cfg.Component(name); current == "" {
+						cfg.SetComponent(name, value)
+						cfg.Sources[name] = matcher.Source()
+					}
+				}
+			}
+		}
+	}
+
+	detectors, err := ParseDetectors(s.WorkspaceDetectors)
+	if err != nil {
+		fail("workspace_detectors", "Invalid Workspace Detector", err.Error())
+	}
+	var pattern *regexp.Regexp
+	if expr := str(s.WorkspacePattern); expr != "" {
+		pattern, err = regexp.Compile(expr)
+		if err != nil {
+			fail("workspace_pattern", "Invalid Workspace Pattern", err.Error())
+		} else if !hasValidGroups(pattern) {
+			fail("workspace_pattern", "Invalid Workspace Pattern",
+				"The pattern must contain named groups such as (?P<workspace>...) or (?P<stage>...) naming provider-level or custom components.")
+		} else {
+			for _, name := range pattern.SubexpNames() {
+				declared[name] = true
+			}
+		}
+	}
+	if len(errs) == 0 && cfg.Workspace == "" {
+		getenv := func(key string) string { return env[key] }
+		if detection := Detect(detectors, pattern, getenv); detection != nil {
+			for name, value := range detection.Components {
+				if current, _ := cfg.Component(name); current == "" {
+					cfg.SetComponent(name, value)
+					cfg.Sources[name] = detection.Source()
+				}
+			}
+		}
+	}
+
+	cfg.Regions = NewRegionTable(s.RegionCodes)
+	cfg.Region = str(s.Region)
+	cfg.RegionStyle = str(s.RegionStyle)
+	if cfg.RegionStyle == "" {
+		cfg.RegionStyle = RegionStyleShort
+	}
+	if err := validateRegionStyle(cfg.RegionStyle); err != nil {
+		fail("region_style", "Invalid Region Style", err.Error())
+	} else if cfg.Environment == "" && cfg.Region != "" {
+		code, err := RegionToCode(cfg.Regions, cfg.Region, cfg.RegionStyle)
+		if err != nil {
+			fail("region", "Unknown Region", err.Error())
+		}
+		cfg.Environment = code
+		cfg.Sources["environment"] = "region " + cfg.Region
+	}
+
+	// Components detected from the path or CI/CD variables fill in custom
+	// components that are not set anywhere else.
+	components := map[string]string{}
+	maps.Copy(components, cfg.Components)
+	maps.Copy(components, s.Components)
+	for _, name := range slices.Sorted(maps.Keys(components)) {
+		if !componentNamePattern.MatchString(name) || !IsCustomComponent(name) || name == "name" || name == "attributes" {
+			fail("components", "Invalid Component Name",
+				fmt.Sprintf("Component name %q must match %s and must not shadow a built-in component or tag.", name, componentNamePattern))
+		}
+		declared[name] = true
+	}
+	for _, name := range s.RequiredComponents {
+		declared[name] = true
+	}
+	// known reports whether name is a component a label can carry, so that
+	// typos such as "enviroment" are not silently left out of IDs.
+	known := func(name string) bool {
+		return !IsCustomComponent(name) || declared[name]
+	}
+	cfg.Components = components
+
+	cfg.Compat = str(s.Compat)
+	if cfg.Compat == "" {
+		cfg.Compat = CompatNone
+	}
+	if err := validateCompat(cfg.Compat); err != nil {
+		fail("compat", "Invalid Compatibility Mode", err.Error())
+	}
+	nullLabel := cfg.Compat == CompatNullLabel
+
+	seen := make(map[string]bool, len(s.LabelOrder))
+	for _, name := range s.LabelOrder {
+		switch {
+		case nullLabel && (!slices.Contains(NullLabelLabels, name) || seen[name]):
+			fail("label_order", "Invalid Label Order",
+				fmt.Sprintf("Label %q is not a null-label label or is listed more than once. Use %s.", name, strings.Join(NullLabelLabels, ", ")))
+		case !nullLabel && (!componentNamePattern.MatchString(name) || seen[name]):
+			fail("label_order", "Invalid Label Order",
+				fmt.Sprintf("Component %q is not a valid component name or is listed more than once.", name))
+		case !nullLabel && !known(name):
+			fail("label_order", "Invalid Label Order",
+				fmt.Sprintf("Component %q is not a built-in component (%s), a resource component (%s) or a configured custom component.", name, strings.Join(BuiltinComponents, ", "), strings.Join(ResourceComponents, ", ")))
+		}
+		seen[name] = true
+	}
+	cfg.LabelOrder = s.LabelOrder
+
+	for _, name := range s.RequiredComponents {
+		if !componentNamePattern.MatchString(name) || slices.Contains(ResourceComponents, name) {
+			fail("required_components", "Invalid Required Component",
+				fmt.Sprintf("Component %q cannot be required. Use one of %s or a custom component.", name, strings.Join(BuiltinComponents, ", ")))
+		}
+	}
+	cfg.RequiredComponents = s.RequiredComponents
+	if nullLabel && cfg.RequiredComponents == nil {
+		// null-label requires no labels.
+		cfg.RequiredComponents = []string{}
+	}
+
+	cfg.AttributesDelimiter = str(s.AttributesDelimiter)
+	for _, name := range s.AttributesComponents {
+		if !componentNamePattern.MatchString(name) || !known(name) {
+			fail("attributes_components", "Invalid Attributes Component",
+				fmt.Sprintf("Component %q is not a built-in component (%s), a resource component (%s) or a configured custom component.", name, strings.Join(BuiltinComponents, ", "), strings.Join(ResourceComponents, ", ")))
+		}
+	}
+	cfg.AttributesComponents = s.AttributesComponents
+	cfg.OmitAttributesTag = s.AttributesTag != nil && !*s.AttributesTag
+
+	cfg.AmbiguityCheck = str(s.AmbiguityCheck)
+	if cfg.AmbiguityCheck == "" {
+		cfg.AmbiguityCheck = CheckOff
+	}
+	if err := validateCheckMode(cfg.AmbiguityCheck); err != nil {
+		fail("ambiguity_check", "Invalid Ambiguity Check", err.Error())
+	}
+	cfg.EscapeDelimiter = s.EscapeDelimiter != nil && *s.EscapeDelimiter
+
+	cfg.DuplicateCheck = str(s.DuplicateCheck)
+	if cfg.DuplicateCheck == "" {
+		cfg.DuplicateCheck = CheckWarn
+	}
+	if err := validateCheckMode(cfg.DuplicateCheck); err != nil {
+		fail("duplicate_check", "Invalid Duplicate Check", err.Error())
+	}
+	cfg.Registry = NewIDRegistry()
+	cfg.SuffixSalt = str(s.SuffixSalt)
+
+	cfg.LegacyNames = s.LegacyNames
+
+	for name := range s.Aliases {
+		if !componentNamePattern.MatchString(name) || slices.Contains(ResourceComponents, name) {
+			fail("aliases", "Invalid Alias Component",
+				fmt.Sprintf("Component %q cannot have aliases. Use one of %s or a custom component.", name, strings.Join(BuiltinComponents, ", ")))
+		}
+	}
+	cfg.Aliases = s.Aliases
+	cfg.RawValueTags = s.RawValueTags != nil && *s.RawValueTags
+
+	for _, setting := range []struct {
+		attr string
+		set  bool
+	}{
+		{"id_length_limit", s.IDLengthLimit != nil},
+		{"regex_replace_chars", s.RegexReplaceChars != nil},
+		{"label_key_case", s.LabelKeyCase != nil},
+		{"label_value_case", s.LabelValueCase != nil},
+		{"labels_as_tags", s.LabelsAsTags != nil},
+	} {
+		if setting.set && !nullLabel {
+			fail(setting.attr, "Null-label Setting Without Compatibility Mode",
+				fmt.Sprintf("%s is only used with compat = %q.", setting.attr, CompatNullLabel))
+		}
+	}
+	if s.IDLengthLimit != nil {
+		cfg.NullLabel.IDLengthLimit = int(*s.IDLengthLimit)
+		if err := validateIDLengthLimit(cfg.NullLabel.IDLengthLimit); err != nil {
+			fail("id_length_limit", "Invalid ID Length Limit", err.Error())
+		}
+	}
+	cfg.NullLabel.RegexReplaceChars = str(s.RegexReplaceChars)
+	if err := validateRegexReplaceChars(cfg.NullLabel.RegexReplaceChars); err != nil {
+		fail("regex_replace_chars", "Invalid Regex Replace Chars", err.Error())
+	}
+	cfg.NullLabel.LabelKeyCase = str(s.LabelKeyCase)
+	if cfg.NullLabel.LabelKeyCase == "" {
+		cfg.NullLabel.LabelKeyCase = DefaultNullLabelKeyCase
+	}
+	if err := validateLetterCase(cfg.NullLabel.LabelKeyCase, false); err != nil {
+		fail("label_key_case", "Invalid Label Key Case", err.Error())
+	}
+	cfg.NullLabel.LabelValueCase = str(s.LabelValueCase)
+	if cfg.NullLabel.LabelValueCase == "" {
+		cfg.NullLabel.LabelValueCase = DefaultNullLabelValueCase
+	}
+	if err := validateLetterCase(cfg.NullLabel.LabelValueCase, true); err != nil {
+		fail("label_value_case", "Invalid Label Value Case", err.Error())
+	}
+	for _, name := range s.LabelsAsTags {
+		if !slices.Contains(NullLabelLabels, name) && name != defaultNullLabelsAsTagsKey {
+			fail("labels_as_tags", "Invalid Labels As Tags",
+				fmt.Sprintf("Label %q is not a null-label label. Use %s or %q.", name, strings.Join(NullLabelLabels, ", "), defaultNullLabelsAsTagsKey))
+		}
+	}
+	cfg.NullLabel.LabelsAsTags = s.LabelsAsTags
+
+	if len(errs) > 0 {
+		return nil, errs
+	}
+
+	cfg.ApplyAliases()
+	return cfg, nil
+}
+
+// hasValidGroups reports whether pattern has at least one named group and
+// every named group names a provider-level or custom component.
+func hasValidGroups(pattern *regexp.Regexp) bool {
+	found := false
+	for _, name := range pattern.SubexpNames() {
+		if name == "" {
+			continue
+		}
+		if !componentNamePattern.MatchString(name) || slices.Contains(ResourceComponents, name) {
+			return false
+		}
+		found = true
+	}
+	return found
+}
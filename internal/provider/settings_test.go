@@ -0,0 +1,196 @@
+package provider
+
+import (
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func ptr[T any](v T) *T { return &v }
+
+func TestEnvSettings(t *testing.T) {
+	s, errs := EnvSettings(map[string]string{
+		"LABEL_TENANT":                 "dpl",
+		"LABEL_STAGE":                  "",
+		"LABEL_ORDER":                  "tenant, stage,,workspace",
+		"LABEL_WORKSPACE_OPAQUE":       "true",
+		"LABEL_COMPONENT_COST_CENTER":  "cc42",
+		"LABEL_ESCAPE_DELIMITER":       "maybe",
+		"LABEL_UNRELATED_SETTING_NAME": "x",
+	})
+
+	if s.Tenant == nil || *s.Tenant != "dpl" {
+		t.Errorf("Tenant = %v, want dpl", s.Tenant)
+	}
+	if s.Stage != nil {
+		t.Errorf("empty LABEL_STAGE should be unset, got %q", *s.Stage)
+	}
+	if want := []string{"tenant", "stage", "workspace"}; !slices.Equal(s.LabelOrder, want) {
+		t.Errorf("LabelOrder = %v, want %v", s.LabelOrder, want)
+	}
+	if s.WorkspaceOpaque == nil || !*s.WorkspaceOpaque {
+		t.Errorf("WorkspaceOpaque = %v, want true", s.WorkspaceOpaque)
+	}
+	if want := map[string]string{"cost_center": "cc42"}; !maps.Equal(s.Components, want) {
+		t.Errorf("Components = %v, want %v", s.Components, want)
+	}
+	if len(errs) != 1 || errs[0].Attribute != "escape_delimiter" {
+		t.Errorf("errs = %v, want one escape_delimiter error", errs)
+	}
+}
+
+func TestMergeSettings(t *testing.T) {
+	got := MergeSettings(
+		Settings{Tenant: ptr("cfg"), Components: map[string]string{"team": "core"}},
+		Settings{Tenant: ptr("env"), Stage: ptr("dev"), Components: map[string]string{"team": "data", "cost_center": "cc42"}},
+		Settings{Stage: ptr("prd"), Workspace: ptr("vpc"), LabelOrder: []string{"tenant", "stage"}},
+	)
+
+	if *got.Tenant != "cfg" || *got.Stage != "dev" || *got.Workspace != "vpc" {
+		t.Errorf("tenant/stage/workspace = %s/%s/%s, want cfg/dev/vpc", *got.Tenant, *got.Stage, *got.Workspace)
+	}
+	if want := map[string]string{"team": "core", "cost_center": "cc42"}; !maps.Equal(got.Components, want) {
+		t.Errorf("Components = %v, want %v", got.Components, want)
+	}
+	if want := []string{"tenant", "stage"}; !slices.Equal(got.LabelOrder, want) {
+		t.Errorf("LabelOrder = %v, want %v", got.LabelOrder, want)
+	}
+}
+
+func TestResolveConfig(t *testing.T) {
+	getwd := func() (string, error) { return "/repo/stacks/sales/api/dev", nil }
+
+	tests := []struct {
+		name        string
+		config      Settings
+		file        Settings
+		env         map[string]string
+		wantID      string
+		wantSources map[string]string
+		wantErr     string
+	}{
+		{
+			name:   "file only",
+			file:   Settings{Tenant: ptr("dpl"), Region: ptr("ap-northeast-2"), Stage: ptr("dev"), Workspace: ptr("vpc")},
+			wantID: "dpl-ane2-sg-dev-vpc",
+			wantSources: map[string]string{
+				"tenant":      "convention file c.json",
+				"environment": "region ap-northeast-2",
+				"stage":       "convention file c.json",
+				"workspace":   "convention file c.json",
+			},
+		},
+		{
+			name:   "config over env over file",
+			config: Settings{Tenant: ptr("cfg")},
+			file:   Settings{Tenant: ptr("file"), Environment: ptr("ane2"), Stage: ptr("prd"), Workspace: ptr("vpc")},
+			env:    map[string]string{"LABEL_TENANT": "env", "LABEL_STAGE": "dev"},
+			wantID: "cfg-ane2-sg-dev-vpc",
+			wantSources: map[string]string{
+				"tenant":      "config",
+				"environment": "convention file c.json",
+				"stage":       "env LABEL_STAGE",
+				"workspace":   "convention file c.json",
+			},
+		},
+		{
+			name:   "workspace from path in convention file",
+			file:   Settings{Tenant: ptr("dpl"), Environment: ptr("ane2"), WorkspaceFromPath: ptr("stacks/**/{stage}")},
+			wantID: "dpl-ane2-sg-dev-sales-api",
+		},
+		{
+			name:   "null-label compatibility",
+			file:   Settings{Namespace: ptr("eg"), Stage: ptr("prod"), Workspace: ptr("vpc"), Compat: ptr(CompatNullLabel)},
+			env:    map[string]string{"LABEL_VALUE_CASE": "upper"},
+			wantID: "EG-PROD",
+		},
+		{
+			name:    "null-label setting without compat",
+			file:    Settings{Tenant: ptr("dpl"), LabelKeyCase: ptr(CaseUpper)},
+			wantErr: "label_key_case",
+		},
+		{
+			name:    "label order outside null-label labels",
+			file:    Settings{Compat: ptr(CompatNullLabel), LabelOrder: []string{"name", "workspace"}},
+			wantErr: "label_order",
+		},
+		{
+			name:    "misspelled component in label order",
+			file:    Settings{Tenant: ptr("dpl"), LabelOrder: []string{"tenant", "enviroment", "resource_type"}},
+			wantErr: "label_order",
+		},
+		{
+			name:   "custom component in label order",
+			file:   Settings{Tenant: ptr("dpl"), Components: map[string]string{"account": "core"}, LabelOrder: []string{"tenant", "account", "resource_type"}},
+			wantID: "dpl-core-sg",
+		},
+		{
+			name:   "path component in label order",
+			file:   Settings{Tenant: ptr("dpl"), Workspace: ptr("vpc"), WorkspaceFromPath: ptr("stacks/*/{team}"), LabelOrder: []string{"tenant", "team", "resource_type", "workspace"}},
+			wantID: "dpl-sg-vpc",
+		},
+		{
+			name:    "unknown attributes component",
+			file:    Settings{Tenant: ptr("dpl"), AttributesComponents: []string{"qualifer"}},
+			wantErr: "attributes_components",
+		},
+		{
+			name:    "invalid check mode",
+			file:    Settings{Tenant: ptr("dpl"), AmbiguityCheck: ptr("loud")},
+			wantErr: "ambiguity_check",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, errs := ResolveConfig(tt.config, tt.file, "convention file c.json", tt.env, getwd)
+			if tt.wantErr != "" {
+				if len(errs) == 0 || errs[0].Attribute != tt.wantErr {
+					t.Fatalf("errs = %v, want %s error", errs, tt.wantErr)
+				}
+				return
+			}
+			if len(errs) > 0 {
+				t.Fatalf("unexpected errors: %v", errs)
+			}
+			if got := GenerateID(cfg, "sg", "", "", ""); got != tt.wantID {
+				t.Errorf("GenerateID = %q, want %q", got, tt.wantID)
+			}
+			for name, want := range tt.wantSources {
+				if got := cfg.Sources[name]; got != want {
+					t.Errorf("Sources[%s] = %q, want %q", name, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestLoadConventionFile(t *testing.T) {
+	dir := t.TempDir()
+
+	good := filepath.Join(dir, "good.json")
+	if err := os.WriteFile(good, []byte(`{"tenant": "dpl", "label_order": ["tenant", "resource_type"], "legacy_names": {"dpl-db": "ordersprod"}}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	f, err := LoadConventionFile(good)
+	if err != nil {
+		t.Fatalf("LoadConventionFile: %v", err)
+	}
+	if *f.Tenant != "dpl" || len(f.LabelOrder) != 2 || f.LegacyNames["dpl-db"] != "ordersprod" {
+		t.Errorf("unexpected settings: %+v", f.Settings)
+	}
+	if want := "convention file " + good; f.Source() != want {
+		t.Errorf("Source() = %q, want %q", f.Source(), want)
+	}
+
+	typo := filepath.Join(dir, "typo.json")
+	if err := os.WriteFile(typo, []byte(`{"tennant": "dpl"}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadConventionFile(typo); err == nil || !strings.Contains(err.Error(), "tennant") {
+		t.Errorf("LoadConventionFile(typo) error = %v, want unknown field error", err)
+	}
+}
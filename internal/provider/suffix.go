@@ -0,0 +1,100 @@
+package provider
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// Unique suffix defaults.
+const (
+	DefaultSuffixLength   = 6
+	DefaultSuffixAlphabet = "abcdefghijklmnopqrstuvwxyz0123456789"
+	MaxSuffixLength       = 32
+	MaxSuffixAlphabet     = 256
+)
+
+// UniqueSuffix derives a deterministic suffix of length characters drawn
+// from alphabet. The same seed always yields the same suffix, so names stay
+// stable across plans without storing anything in state.
+//
+// The seed values are hashed with SHA-256, the digest is extended with a
+// second round to 512 bits and read as a number in base len(alphabet).
+func UniqueSuffix(seed []string, length int, alphabet string) string {
+	h := sha256.New()
+	for _, s := range seed {
+		// Length-prefix each value so ["ab", "c"] and ["a", "bc"] differ.
+		fmt.Fprintf(h, "%d:%s;", len(s), s)
+	}
+	first := h.Sum(nil)
+	second := sha256.Sum256(first)
+
+	chars := []rune(alphabet)
+	n := new(big.Int).SetBytes(append(first, second[:]...))
+	base := big.NewInt(int64(len(chars)))
+	mod := new(big.Int)
+
+	out := make([]rune, length)
+	for i := range out {
+		n.DivMod(n, base, mod)
+		out[i] = chars[mod.Int64()]
+	}
+	return string(out)
+}
+
+func validateSuffix(length int, alphabet string) error {
+	if length < 1 || length > MaxSuffixLength {
+		return fmt.Errorf("suffix length must be between 1 and %d, got %d", MaxSuffixLength, length)
+	}
+	chars := []rune(alphabet)
+	if len(chars) < 2 || len(chars) > MaxSuffixAlphabet {
+		return fmt.Errorf("suffix alphabet must have between 2 and %d characters, got %d", MaxSuffixAlphabet, len(chars))
+	}
+	seen := map[rune]bool{}
+	for _, c := range chars {
+		if seen[c] {
+			return fmt.Errorf("suffix alphabet %q repeats %q", alphabet, c)
+		}
+		seen[c] = true
+	}
+	return nil
+}
+
+// RandomSuffix returns length characters drawn uniformly from alphabet
+// using crypto/rand.
+func RandomSuffix(length int, alphabet string) (string, error) {
+	chars := []rune(alphabet)
+	max := big.NewInt(int64(len(chars)))
+
+	out := make([]rune, length)
+	for i := range out {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		out[i] = chars[n.Int64()]
+	}
+	return string(out), nil
+}
+
+// ComposeName joins id and suffix with delimiter. When maxLength is set and
+// the result would be longer, id is shortened (and stripped of a trailing
+// delimiter) so that the suffix is always kept whole.
+func ComposeName(id string, suffix string, delimiter string, maxLength int) (string, error) {
+	name := id + delimiter + suffix
+	if maxLength <= 0 || len([]rune(name)) <= maxLength {
+		return name, nil
+	}
+
+	keep := maxLength - len([]rune(delimiter)) - len([]rune(suffix))
+	if keep <= 0 {
+		return "", fmt.Errorf("max length %d leaves no room for the ID next to a %d-character suffix", maxLength, len([]rune(suffix)))
+	}
+	base := string([]rune(id)[:keep])
+	for delimiter != "" && strings.HasSuffix(base, delimiter) {
+		base = strings.TrimSuffix(base, delimiter)
+	}
+	return base + delimiter + suffix, nil
+}
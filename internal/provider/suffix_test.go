@@ -0,0 +1,126 @@
+package provider
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestUniqueSuffix(t *testing.T) {
+	tests := []struct {
+		name     string
+		seed     []string
+		length   int
+		alphabet string
+		want     string
+	}{
+		// Pinned values: a change here renames every suffixed resource.
+		{"id and salt", []string{"dpl-ane2-s3-dev-logs-sales-api", "acme"}, 6, DefaultSuffixAlphabet, "5a45vm"},
+		{"account seed hex", []string{"123456789012", "acme"}, 8, "0123456789abcdef", "3979a77f"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := UniqueSuffix(tt.seed, tt.length, tt.alphabet)
+			if got != tt.want {
+				t.Errorf("UniqueSuffix() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUniqueSuffix_Properties(t *testing.T) {
+	seed := []string{"123456789012", "dpl", "sales-api"}
+
+	if a, b := UniqueSuffix(seed, 6, DefaultSuffixAlphabet), UniqueSuffix(seed, 6, DefaultSuffixAlphabet); a != b {
+		t.Errorf("suffix not deterministic: %q != %q", a, b)
+	}
+	if a, b := UniqueSuffix([]string{"ab", "c"}, 8, DefaultSuffixAlphabet), UniqueSuffix([]string{"a", "bc"}, 8, DefaultSuffixAlphabet); a == b {
+		t.Errorf("seed boundaries ignored: both %q", a)
+	}
+
+	for _, alphabet := range []string{"01", "abcdef", DefaultSuffixAlphabet, "가나다라마"} {
+		got := UniqueSuffix(seed, MaxSuffixLength, alphabet)
+		if n := len([]rune(got)); n != MaxSuffixLength {
+			t.Errorf("len(%q) = %d, want %d", got, n, MaxSuffixLength)
+		}
+		for _, c := range got {
+			if !strings.ContainsRune(alphabet, c) {
+				t.Errorf("suffix %q contains %q outside alphabet %q", got, c, alphabet)
+			}
+		}
+	}
+}
+
+func TestValidateSuffix(t *testing.T) {
+	tests := []struct {
+		length   int
+		alphabet string
+		wantErr  bool
+	}{
+		{6, DefaultSuffixAlphabet, false},
+		{MaxSuffixLength, "01", false},
+		{0, DefaultSuffixAlphabet, true},
+		{MaxSuffixLength + 1, DefaultSuffixAlphabet, true},
+		{6, "a", true},
+		{6, "abca", true},
+	}
+
+	for _, tt := range tests {
+		err := validateSuffix(tt.length, tt.alphabet)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("validateSuffix(%d, %q) error = %v, wantErr %v", tt.length, tt.alphabet, err, tt.wantErr)
+		}
+	}
+}
+
+func TestRandomSuffix(t *testing.T) {
+	seen := map[string]bool{}
+	for i := 0; i < 20; i++ {
+		got, err := RandomSuffix(8, DefaultSuffixAlphabet)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(got) != 8 {
+			t.Errorf("len(%q) = %d, want 8", got, len(got))
+		}
+		for _, c := range got {
+			if !strings.ContainsRune(DefaultSuffixAlphabet, c) {
+				t.Errorf("suffix %q contains %q outside alphabet", got, c)
+			}
+		}
+		seen[got] = true
+	}
+	if len(seen) < 20 {
+		t.Errorf("got %d distinct suffixes out of 20", len(seen))
+	}
+}
+
+func TestComposeName(t *testing.T) {
+	tests := []struct {
+		name      string
+		id        string
+		delimiter string
+		maxLength int
+		want      string
+		wantErr   bool
+	}{
+		{"no limit", "dpl-ane2-s3-dev-logs-sales-api", "-", 0, "dpl-ane2-s3-dev-logs-sales-api-k3x9", false},
+		{"within limit", "dpl-ane2-s3-dev-logs", "-", 63, "dpl-ane2-s3-dev-logs-k3x9", false},
+		{"truncated", "dpl-ane2-s3-dev-logs-sales-api", "-", 20, "dpl-ane2-s3-dev-k3x9", false},
+		{"trailing delimiter stripped", "dpl-ane2-s3-dev-logs-sales-api", "-", 21, "dpl-ane2-s3-dev-k3x9", false},
+		{"underscore", "dpl_ane2_db_dev_orders", "_", 17, "dpl_ane2_db_k3x9", false},
+		{"no room", "dpl-ane2-s3-dev", "-", 5, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ComposeName(tt.id, "k3x9", tt.delimiter, tt.maxLength)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ComposeName() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ComposeName() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package provider
+
+import "strings"
+
+// Target describes how a Terraform resource type is named: the resource_type
+// abbreviation its label uses and the attribute that holds the name.
+type Target struct {
+	// Abbreviation is the resource_type passed to the label data source.
+	Abbreviation string `json:"abbreviation"`
+	// NameAttribute is the attribute that holds the name, e.g. "bucket", or
+	// "tags.Name" for resources that are only named by tag.
+	NameAttribute string `json:"name_attribute"`
+	// Delimiter overrides the convention delimiter, e.g. "_" for Glue.
+	Delimiter string `json:"delimiter,omitempty"`
+	// ReplaceOnRename is set when changing the name replaces the resource.
+	ReplaceOnRename bool `json:"replace_on_rename,omitempty"`
+	// Tagged is set when the resource supports tags.
+	Tagged bool `json:"tagged,omitempty"`
+}
+
+// NameTag is the NameAttribute of resources named by their Name tag.
+const NameTag = "tags.Name"
+
+// DefaultTargets is the built-in catalog of resource types whose names
+// follow the convention. A convention file can extend or override it.
+var DefaultTargets = map[string]Target{
+	"aws_cloudwatch_log_group":  {Abbreviation: "log", NameAttribute: "name", ReplaceOnRename: true, Tagged: true},
+	"aws_db_instance":           {Abbreviation: "db", NameAttribute: "identifier", ReplaceOnRename: true, Tagged: true},
+	"aws_dynamodb_table":        {Abbreviation: "ddb", NameAttribute: "name", ReplaceOnRename: true, Tagged: true},
+	"aws_ecr_repository":        {Abbreviation: "ecr", NameAttribute: "name", ReplaceOnRename: true, Tagged: true},
+	"aws_ecs_cluster":           {Abbreviation: "ecs", NameAttribute: "name", ReplaceOnRename: true, Tagged: true},
+	"aws_eks_cluster":           {Abbreviation: "eks", NameAttribute: "name", ReplaceOnRename: true, Tagged: true},
+	"aws_emr_cluster":           {Abbreviation: "emr", NameAttribute: "name", ReplaceOnRename: true, Tagged: true},
+	"aws_glue_catalog_database": {Abbreviation: "db", NameAttribute: "name", Delimiter: "_", ReplaceOnRename: true},
+	"aws_glue_job":              {Abbreviation: "glue", NameAttribute: "name", ReplaceOnRename: true, Tagged: true},
+	"aws_iam_policy":            {Abbreviation: "policy", NameAttribute: "name", ReplaceOnRename: true, Tagged: true},
+	"aws_iam_role":              {Abbreviation: "role", NameAttribute: "name", ReplaceOnRename: true, Tagged: true},
+	"aws_instance":              {Abbreviation: "ec2", NameAttribute: NameTag, Tagged: true},
+	"aws_lambda_function":       {Abbreviation: "lambda", NameAttribute: "function_name", ReplaceOnRename: true, Tagged: true},
+	"aws_lb":                    {Abbreviation: "alb", NameAttribute: "name", ReplaceOnRename: true, Tagged: true},
+	"aws_msk_cluster":           {Abbreviation: "msk", NameAttribute: "cluster_name", ReplaceOnRename: true, Tagged: true},
+	"aws_s3_bucket":             {Abbreviation: "s3", NameAttribute: "bucket", ReplaceOnRename: true, Tagged: true},
+	"aws_security_group":        {Abbreviation: "sg", NameAttribute: "name", ReplaceOnRename: true, Tagged: true},
+	"aws_sns_topic":             {Abbreviation: "sns", NameAttribute: "name", ReplaceOnRename: true, Tagged: true},
+	"aws_sqs_queue":             {Abbreviation: "sqs", NameAttribute: "name", ReplaceOnRename: true, Tagged: true},
+	"aws_subnet":                {Abbreviation: "subnet", NameAttribute: NameTag, Tagged: true},
+	"aws_vpc":                   {Abbreviation: "vpc", NameAttribute: NameTag, Tagged: true},
+}
+
+// Name returns the name held in a resource's attribute values, e.g. the
+// planned values of a plan or the attributes of a state file.
+func (t Target) Name(values map[string]any) (string, bool) {
+	attr, tag, byTag := strings.Cut(t.NameAttribute, ".")
+	v, ok := values[attr]
+	if byTag {
+		tags, _ := v.(map[string]any)
+		v, ok = tags[tag]
+	}
+	name, isString := v.(string)
+	return name, ok && isString
+}
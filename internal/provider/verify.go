@@ -0,0 +1,97 @@
+package provider
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// ConventionTest is an example declared in the tests of a convention file:
+// the inputs of a label data source and the name and tags they must
+// generate, e.g.
+//
+//	{
+//	  "name": "emr security group",
+//	  "resource_type": "sg",
+//	  "qualifier": "emr",
+//	  "components": {"stage": "dev", "workspace": "sales-api"},
+//	  "id": "dpl-ane2-sg-dev-emr-sales-api",
+//	  "tags": {"Stage": "dev"}
+//	}
+type ConventionTest struct {
+	// Name describes the test in reports. It defaults to the expected ID.
+	Name         string `json:"name,omitempty"`
+	ResourceType string `json:"resource_type"`
+	Qualifier    string `json:"qualifier,omitempty"`
+	InstanceKey  string `json:"instance_key,omitempty"`
+	Delimiter    string `json:"delimiter,omitempty"`
+	LegacyKey    string `json:"legacy_key,omitempty"`
+	// Components override provider-level components, built-in or custom,
+	// like the overrides of the data source.
+	Components map[string]string `json:"components,omitempty"`
+
+	// ID is the expected id, the legacy name when one applies.
+	ID string `json:"id"`
+	// Tags are expected tags. Tags that are not listed are not checked.
+	Tags map[string]string `json:"tags,omitempty"`
+}
+
+// ConventionTestDiff is a value a test expects but the convention does not
+// generate.
+type ConventionTestDiff struct {
+	// Field is "id" or "tags.<key>".
+	Field string
+	Want  string
+	Got   string
+	// Missing is set when the convention generates no such tag.
+	Missing bool
+}
+
+// Title returns the name of the test, or its expected ID.
+func (t ConventionTest) Title() string {
+	if t.Name != "" {
+		return t.Name
+	}
+	return t.ID
+}
+
+// Run generates the label of the test with cfg and returns its differences
+// from the expected values, in field order. An error means the test cannot
+// run, e.g. because a required component has no value.
+func (t ConventionTest) Run(cfg *LabelConfig) ([]ConventionTestDiff, error) {
+	if t.ResourceType == "" {
+		return nil, fmt.Errorf("resource_type is required")
+	}
+	if t.ID == "" {
+		return nil, fmt.Errorf("id is required")
+	}
+	for name := range t.Components {
+		if slices.Contains(ResourceComponents, name) {
+			return nil, fmt.Errorf("component %q is a test input, not a component override", name)
+		}
+	}
+
+	cfg = cfg.WithOverrides(t.Components)
+	if missing := cfg.MissingComponents(); len(missing) > 0 {
+		return nil, fmt.Errorf("missing required components: %s", strings.Join(missing, ", "))
+	}
+
+	id := GenerateID(cfg, t.ResourceType, t.Qualifier, t.InstanceKey, t.Delimiter)
+	tags := GenerateTags(cfg, t.ResourceType, t.Qualifier, t.InstanceKey, t.Delimiter)
+	if legacy, ok := cfg.LegacyName(t.LegacyKey, id); ok {
+		id = legacy
+	}
+
+	var diffs []ConventionTestDiff
+	if id != t.ID {
+		diffs = append(diffs, ConventionTestDiff{Field: "id", Want: t.ID, Got: id})
+	}
+	for _, key := range slices.Sorted(maps.Keys(t.Tags)) {
+		got, ok := tags[key]
+		if !ok || got != t.Tags[key] {
+			diffs = append(diffs, ConventionTestDiff{Field: "tags." + key, Want: t.Tags[key], Got: got, Missing: !ok})
+		}
+	}
+	return diffs, nil
+}
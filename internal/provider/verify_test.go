@@ -0,0 +1,89 @@
+package provider
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestConventionTest_Run(t *testing.T) {
+	cfg := &LabelConfig{
+		Tenant:      "dpl",
+		Environment: "ane2",
+		Stage:       "dev",
+		Workspace:   "sales-api",
+		Delimiter:   "-",
+		LegacyNames: map[string]string{"dpl-ane2-db-prd-sales-api-orders": "ordersprod"},
+	}
+
+	tests := []struct {
+		name    string
+		test    ConventionTest
+		want    []ConventionTestDiff
+		wantErr string
+	}{
+		{
+			name: "pass",
+			test: ConventionTest{ResourceType: "sg", Qualifier: "emr", ID: "dpl-ane2-sg-dev-emr-sales-api", Tags: map[string]string{"Stage": "dev"}},
+		},
+		{
+			name: "component override",
+			test: ConventionTest{ResourceType: "kms", Components: map[string]string{"stage": "shared", "workspace": "core"}, ID: "dpl-ane2-kms-shared-core"},
+		},
+		{
+			name: "legacy name",
+			test: ConventionTest{ResourceType: "db", InstanceKey: "orders", Components: map[string]string{"stage": "prd"}, ID: "ordersprod"},
+		},
+		{
+			name: "mismatches",
+			test: ConventionTest{ResourceType: "sg", ID: "dpl-ane2-sg-prd-sales-api", Tags: map[string]string{"Stage": "prd", "Owner": "data", "Tenant": "dpl"}},
+			want: []ConventionTestDiff{
+				{Field: "id", Want: "dpl-ane2-sg-prd-sales-api", Got: "dpl-ane2-sg-dev-sales-api"},
+				{Field: "tags.Owner", Want: "data", Missing: true},
+				{Field: "tags.Stage", Want: "prd", Got: "dev"},
+			},
+		},
+		{
+			name:    "no resource type",
+			test:    ConventionTest{ID: "x"},
+			wantErr: "resource_type is required",
+		},
+		{
+			name:    "resource component override",
+			test:    ConventionTest{ResourceType: "sg", ID: "x", Components: map[string]string{"qualifier": "emr"}},
+			wantErr: `component "qualifier" is a test input`,
+		},
+		{
+			name:    "missing component",
+			test:    ConventionTest{ResourceType: "sg", ID: "x", Components: map[string]string{"stage": ""}},
+			wantErr: "missing required components: stage",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.test.Run(cfg)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Run() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Run() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Run() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConventionTest_Title(t *testing.T) {
+	if got := (ConventionTest{Name: "emr", ID: "x"}).Title(); got != "emr" {
+		t.Errorf("Title() = %q, want %q", got, "emr")
+	}
+	if got := (ConventionTest{ID: "x"}).Title(); got != "x" {
+		t.Errorf("Title() = %q, want %q", got, "x")
+	}
+}
@@ -0,0 +1,169 @@
+package provider
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// PathMatcher derives components from the Terraform working directory.
+//
+// Glob patterns are matched against the trailing directories of the path:
+// "*" captures one directory and "**" one or more into the workspace, and
+// "{name}" captures one directory as the named component. For example
+// "stacks/**/{stage}" matches ".../stacks/sales/api/dev" as
+// workspace "sales-api" and stage "dev".
+//
+// Patterns prefixed with "regex:" are regular expressions matched against
+// the slash-separated path. Named groups set components and unnamed groups
+// are joined into the workspace.
+type PathMatcher struct {
+	pattern string
+	join    string
+	glob    []string
+	regex   *regexp.Regexp
+}
+
+// NewPathMatcher compiles a workspace_from_path pattern. Captured workspace
+// directories are joined with join (default "-").
+func NewPathMatcher(pattern string, join string) (*PathMatcher, error) {
+	if join == "" {
+		join = "-"
+	}
+	m := &PathMatcher{pattern: pattern, join: join}
+
+	if expr, ok := strings.CutPrefix(pattern, "regex:"); ok {
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, err
+		}
+		if re.NumSubexp() == 0 {
+			return nil, fmt.Errorf("pattern %q has no capture groups", expr)
+		}
+		m.regex = re
+		return m, nil
+	}
+
+	for _, seg := range strings.Split(strings.Trim(pattern, "/"), "/") {
+		if seg == "" {
+			return nil, fmt.Errorf("pattern %q contains an empty directory", pattern)
+		}
+		if name, ok := strings.CutPrefix(seg, "{"); ok {
+			name, ok = strings.CutSuffix(name, "}")
+			if !ok || !componentNamePattern.MatchString(name) {
+				return nil, fmt.Errorf("invalid capture %q in pattern %q", seg, pattern)
+			}
+		}
+		m.glob = append(m.glob, seg)
+	}
+	return m, nil
+}
+
+// Source describes the matcher for provenance records.
+func (m *PathMatcher) Source() string {
+	return "path " + m.pattern
+}
+
+// Components returns the components other than the workspace the pattern
+// can capture.
+func (m *PathMatcher) Components() []string {
+	var names []string
+	if m.regex != nil {
+		for _, name := range m.regex.SubexpNames() {
+			if name != "" && name != "workspace" {
+				names = append(names, name)
+			}
+		}
+		return names
+	}
+	for _, seg := range m.glob {
+		if name, ok := strings.CutPrefix(seg, "{"); ok {
+			names = append(names, strings.TrimSuffix(name, "}"))
+		}
+	}
+	return names
+}
+
+// Match applies the pattern to dir. Captured workspace directories are
+// joined with the matcher's join string so the workspace splits into one
+// segment per directory.
+func (m *PathMatcher) Match(dir string) (map[string]string, bool) {
+	dir = filepath.ToSlash(filepath.Clean(dir))
+
+	if m.regex != nil {
+		match := m.regex.FindStringSubmatch(dir)
+		if match == nil {
+			return nil, false
+		}
+		components := map[string]string{}
+		var workspace []string
+		for i, name := range m.regex.SubexpNames() {
+			if i == 0 || match[i] == "" {
+				continue
+			}
+			value := strings.ReplaceAll(strings.Trim(match[i], "/"), "/", m.join)
+			switch name {
+			case "":
+				workspace = append(workspace, value)
+			case "workspace":
+				workspace = append(workspace, value)
+			default:
+				components[name] = value
+			}
+		}
+		if len(workspace) > 0 {
+			components["workspace"] = strings.Join(workspace, m.join)
+		}
+		return components, true
+	}
+
+	dirs := strings.Split(strings.Trim(dir, "/"), "/")
+	for start := range dirs {
+		components := map[string]string{}
+		var workspace []string
+		if matchGlob(m.glob, dirs[start:], components, &workspace) {
+			if len(workspace) > 0 {
+				components["workspace"] = strings.Join(workspace, m.join)
+			}
+			return components, true
+		}
+	}
+	return nil, false
+}
+
+// matchGlob matches the pattern against the full remaining directories.
+func matchGlob(pattern []string, dirs []string, components map[string]string, workspace *[]string) bool {
+	if len(pattern) == 0 {
+		return len(dirs) == 0
+	}
+	if len(dirs) == 0 {
+		return false
+	}
+
+	seg := pattern[0]
+	switch {
+	case seg == "**":
+		for n := len(dirs); n >= 1; n-- {
+			captured := append(append([]string{}, *workspace...), dirs[:n]...)
+			sub := map[string]string{}
+			if matchGlob(pattern[1:], dirs[n:], sub, &captured) {
+				*workspace = captured
+				for k, v := range sub {
+					components[k] = v
+				}
+				return true
+			}
+		}
+		return false
+	case seg == "*":
+		*workspace = append(*workspace, dirs[0])
+	case strings.HasPrefix(seg, "{"):
+		components[strings.Trim(seg, "{}")] = dirs[0]
+	default:
+		if ok, _ := filepath.Match(seg, dirs[0]); !ok {
+			return false
+		}
+	}
+	return matchGlob(pattern[1:], dirs[1:], components, workspace)
+}
@@ -0,0 +1,103 @@
+package provider
+
+import (
+	"testing"
+)
+
+func TestPathMatcher(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern string
+		dir     string
+		wantOK  bool
+		want    map[string]string
+	}{
+		{
+			name:    "workspace and stage",
+			pattern: "stacks/**/{stage}",
+			dir:     "/home/ci/repo/stacks/sales/api/dev",
+			wantOK:  true,
+			want:    map[string]string{"workspace": "sales-api", "stage": "dev"},
+		},
+		{
+			name:    "single directory workspace",
+			pattern: "stacks/**/{stage}",
+			dir:     "/home/ci/repo/stacks/vpc/prd",
+			wantOK:  true,
+			want:    map[string]string{"workspace": "vpc", "stage": "prd"},
+		},
+		{
+			name:    "star captures one directory",
+			pattern: "live/{environment}/*",
+			dir:     "/repo/live/ane2/core",
+			wantOK:  true,
+			want:    map[string]string{"environment": "ane2", "workspace": "core"},
+		},
+		{
+			name:    "literal glob segment",
+			pattern: "stack-*/**",
+			dir:     "/repo/stack-a/sales/api",
+			wantOK:  true,
+			want:    map[string]string{"workspace": "sales-api"},
+		},
+		{
+			name:    "no match",
+			pattern: "stacks/**/{stage}",
+			dir:     "/home/ci/repo/modules/vpc",
+			wantOK:  false,
+		},
+		{
+			name:    "regex with named and unnamed groups",
+			pattern: `regex:stacks/(.+)/(?P<stage>[^/]+)$`,
+			dir:     "/repo/stacks/sales/api/orders/stg",
+			wantOK:  true,
+			want:    map[string]string{"workspace": "sales-api-orders", "stage": "stg"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := NewPathMatcher(tt.pattern, "")
+			if err != nil {
+				t.Fatalf("NewPathMatcher(%q) error: %v", tt.pattern, err)
+			}
+			got, ok := m.Match(tt.dir)
+			if ok != tt.wantOK {
+				t.Fatalf("Match(%q) ok = %v, want %v", tt.dir, ok, tt.wantOK)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("Match(%q) = %v, want %v", tt.dir, got, tt.want)
+			}
+			for k, v := range tt.want {
+				if got[k] != v {
+					t.Errorf("Match(%q)[%q] = %q, want %q", tt.dir, k, got[k], v)
+				}
+			}
+		})
+	}
+}
+
+func TestNewPathMatcher_Invalid(t *testing.T) {
+	for _, pattern := range []string{
+		"stacks//{stage}",
+		"stacks/{Stage}",
+		"stacks/{stage",
+		"regex:stacks/.+",
+		"regex:(",
+	} {
+		if _, err := NewPathMatcher(pattern, ""); err == nil {
+			t.Errorf("NewPathMatcher(%q) expected error", pattern)
+		}
+	}
+}
+
+func TestPathMatcher_Join(t *testing.T) {
+	m, err := NewPathMatcher("stacks/**", ".")
+	if err != nil {
+		t.Fatalf("NewPathMatcher() error: %v", err)
+	}
+	got, ok := m.Match("/repo/stacks/sales/api")
+	if !ok || got["workspace"] != "sales.api" {
+		t.Errorf("Match() = %v, %v, want workspace %q", got, ok, "sales.api")
+	}
+}
@@ -3,12 +3,20 @@ package main
 import (
 	"context"
 	"log"
+	"os"
 
+	"github.com/cloudfluent/terraform-provider-label/internal/cli"
 	"github.com/cloudfluent/terraform-provider-label/internal/provider"
 	"github.com/hashicorp/terraform-plugin-framework/providerserver"
 )
 
 func main() {
+	// Terraform sets the magic cookie when it launches the plugin. Without
+	// it, arguments select a CLI subcommand.
+	if os.Getenv("TF_PLUGIN_MAGIC_COOKIE") == "" && len(os.Args) > 1 {
+		os.Exit(cli.Run(os.Args[1:], provider.Environ(os.Environ()), os.Stdin, os.Stdout, os.Stderr))
+	}
+
 	err := providerserver.Serve(context.Background(), provider.New, providerserver.ServeOpts{
 		Address: "registry.terraform.io/cloudfluent/label",
 	})